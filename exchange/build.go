@@ -13,6 +13,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/nojima/httpie-go/input"
 	"github.com/nojima/httpie-go/version"
@@ -37,13 +38,19 @@ func BuildHTTPRequest(in *input.Input, options *Options) (*http.Request, error)
 		return nil, err
 	}
 
-	bodyTuple, err := buildHTTPBody(in)
+	charset := requestCharset(header, options)
+	bodyTuple, err := buildHTTPBody(in, options.BodyFormat, charset)
 	if err != nil {
 		return nil, err
 	}
 
-	if header.Get("Content-Type") == "" && bodyTuple.contentType != "" {
-		header.Set("Content-Type", bodyTuple.contentType)
+	if contentType := header.Get("Content-Type"); contentType == "" {
+		if bodyTuple.contentType != "" {
+			header.Set("Content-Type", bodyTuple.contentType)
+		}
+	} else if charset != "" && hasCharset(bodyTuple.contentType) {
+		// The body is encoded in charset, which the given Content-Type has to declare
+		header.Set("Content-Type", setCharset(contentType, charset))
 	}
 	if header.Get("User-Agent") == "" {
 		header.Set("User-Agent", fmt.Sprintf("httpie-go/%s", version.Current()))
@@ -96,22 +103,25 @@ func buildHTTPHeader(in *input.Input) (http.Header, error) {
 	return header, nil
 }
 
-func buildHTTPBody(in *input.Input) (bodyTuple, error) {
+// buildHTTPBody builds the body of a request. Data items are serialized in
+// format. Values of form bodies and raw bodies are encoded in charset unless it
+// is empty.
+func buildHTTPBody(in *input.Input, format BodyFormat, charset string) (bodyTuple, error) {
 	switch in.Body.BodyType {
 	case input.EmptyBody:
 		return bodyTuple{}, nil
 	case input.JSONBody:
-		return buildJSONBody(in)
+		return buildJSONBody(in, format)
 	case input.FormBody:
-		return buildFormBody(in)
+		return buildFormBody(in, charset)
 	case input.RawBody:
-		return buildRawBody(in)
+		return buildRawBody(in, charset)
 	default:
 		return bodyTuple{}, errors.Errorf("unknown body type: %v", in.Body.BodyType)
 	}
 }
 
-func buildJSONBody(in *input.Input) (bodyTuple, error) {
+func buildJSONBody(in *input.Input, format BodyFormat) (bodyTuple, error) {
 	obj := map[string]interface{}{}
 	for _, field := range in.Body.Fields {
 		value, err := resolveFieldValue(field)
@@ -126,14 +136,26 @@ func buildJSONBody(in *input.Input) (bodyTuple, error) {
 			return bodyTuple{}, err
 		}
 		var v interface{}
-		if err := json.Unmarshal([]byte(value), &v); err != nil {
-			return bodyTuple{}, errors.Wrapf(err, "parsing JSON value of '%s'", field.Name)
+		if format == JSONFormat {
+			if err := json.Unmarshal([]byte(value), &v); err != nil {
+				return bodyTuple{}, errors.Wrapf(err, "parsing JSON value of '%s'", field.Name)
+			}
+		} else {
+			// Numbers are kept as they are written to be encoded as integers or floats
+			decoder := json.NewDecoder(strings.NewReader(value))
+			decoder.UseNumber()
+			if err := decoder.Decode(&v); err != nil {
+				return bodyTuple{}, errors.Wrapf(err, "parsing JSON value of '%s'", field.Name)
+			}
+			if err := decoder.Decode(&struct{}{}); err != io.EOF {
+				return bodyTuple{}, errors.Errorf("parsing JSON value of '%s': invalid data after top-level value", field.Name)
+			}
 		}
 		obj[field.Name] = v
 	}
-	body, err := json.Marshal(obj)
+	body, contentType, err := marshalBody(obj, format)
 	if err != nil {
-		return bodyTuple{}, errors.Wrap(err, "marshaling JSON of HTTP body")
+		return bodyTuple{}, err
 	}
 	return bodyTuple{
 		body: ioutil.NopCloser(bytes.NewReader(body)),
@@ -141,26 +163,37 @@ func buildJSONBody(in *input.Input) (bodyTuple, error) {
 			return ioutil.NopCloser(bytes.NewReader(body)), nil
 		},
 		contentLength: int64(len(body)),
-		contentType:   "application/json",
+		contentType:   contentType,
 	}, nil
 }
 
-func buildFormBody(in *input.Input) (bodyTuple, error) {
+func buildFormBody(in *input.Input, charset string) (bodyTuple, error) {
 	if len(in.Body.Files) > 0 {
 		return buildMultipartBody(in)
 	} else {
-		return buildURLEncodedBody(in)
+		return buildURLEncodedBody(in, charset)
 	}
 }
 
-func buildURLEncodedBody(in *input.Input) (bodyTuple, error) {
+func buildURLEncodedBody(in *input.Input, charset string) (bodyTuple, error) {
 	form := url.Values{}
 	for _, field := range in.Body.Fields {
 		value, err := resolveFieldValue(field)
 		if err != nil {
 			return bodyTuple{}, err
 		}
-		form.Add(field.Name, value)
+		name, err := encodeString(field.Name, charset)
+		if err != nil {
+			return bodyTuple{}, err
+		}
+		value, err = encodeString(value, charset)
+		if err != nil {
+			return bodyTuple{}, err
+		}
+		form.Add(name, value)
+	}
+	if charset == "" {
+		charset = "utf-8"
 	}
 	body := form.Encode()
 	return bodyTuple{
@@ -169,7 +202,7 @@ func buildURLEncodedBody(in *input.Input) (bodyTuple, error) {
 			return ioutil.NopCloser(strings.NewReader(body)), nil
 		},
 		contentLength: int64(len(body)),
-		contentType:   "application/x-www-form-urlencoded; charset=utf-8",
+		contentType:   "application/x-www-form-urlencoded; charset=" + charset,
 	}, nil
 }
 
@@ -289,14 +322,25 @@ func needEscape(s string) bool {
 	return false
 }
 
-func buildRawBody(in *input.Input) (bodyTuple, error) {
+func buildRawBody(in *input.Input, charset string) (bodyTuple, error) {
+	body := in.Body.Raw
+	contentType := "application/json"
+	// Bodies that are not UTF-8 are regarded as already encoded in charset.
+	if charset != "" && utf8.Valid(body) {
+		encoded, err := encodeString(string(body), charset)
+		if err != nil {
+			return bodyTuple{}, err
+		}
+		body = []byte(encoded)
+		contentType += "; charset=" + charset
+	}
 	return bodyTuple{
-		body: ioutil.NopCloser(bytes.NewReader(in.Body.Raw)),
+		body: ioutil.NopCloser(bytes.NewReader(body)),
 		getBody: func() (io.ReadCloser, error) {
-			return ioutil.NopCloser(bytes.NewReader(in.Body.Raw)), nil
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
 		},
-		contentLength: int64(len(in.Body.Raw)),
-		contentType:   "application/json",
+		contentLength: int64(len(body)),
+		contentType:   contentType,
 	}, nil
 }
 
@@ -14,8 +14,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/nojima/httpie-go/input"
 	"github.com/nojima/httpie-go/version"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 func parseURL(t *testing.T, rawurl string) *url.URL {
@@ -225,7 +227,7 @@ func TestBuildHTTPBody_EmptyBody(t *testing.T) {
 	in := &input.Input{Body: body}
 
 	// Exercise
-	actual, err := buildHTTPBody(in)
+	actual, err := buildHTTPBody(in, JSONFormat, "")
 	if err != nil {
 		t.Fatalf("unexpected error: err=%+v", err)
 	}
@@ -255,7 +257,7 @@ func TestBuildHTTPBody_JSONBody(t *testing.T) {
 	in := &input.Input{Body: body}
 
 	// Exercise
-	bodyTuple, err := buildHTTPBody(in)
+	bodyTuple, err := buildHTTPBody(in, JSONFormat, "")
 	if err != nil {
 		t.Fatalf("unexpected error: err=%+v", err)
 	}
@@ -280,6 +282,135 @@ func TestBuildHTTPBody_JSONBody(t *testing.T) {
 	}
 }
 
+func TestBuildHTTPBody_JSONBody_Numbers(t *testing.T) {
+	in := &input.Input{
+		Body: input.Body{
+			BodyType: input.JSONBody,
+			RawJSONFields: []input.Field{
+				{Name: "float", Value: "1.0"},
+				{Name: "exp", Value: "1e3"},
+			},
+		},
+	}
+	bodyTuple, err := buildHTTPBody(in, JSONFormat, "")
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	// Numbers are normalized as encoding/json does
+	expected := `{"exp":1000,"float":1}`
+	if actual := readAll(t, bodyTuple.body); actual != expected {
+		t.Errorf("unexpected body: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestBuildHTTPBody_JSONBody_Invalid(t *testing.T) {
+	testCases := []struct {
+		value   string
+		formats []BodyFormat
+	}{
+		{value: `[1] junk`, formats: []BodyFormat{JSONFormat, MsgpackFormat, CBORFormat}},
+		{value: `[1] [2]`, formats: []BodyFormat{JSONFormat, MsgpackFormat, CBORFormat}},
+		{value: `{"a": 1`, formats: []BodyFormat{JSONFormat, MsgpackFormat, CBORFormat}},
+		{value: ``, formats: []BodyFormat{JSONFormat, MsgpackFormat, CBORFormat}},
+		{value: `1e400`, formats: []BodyFormat{JSONFormat}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.value, func(t *testing.T) {
+			in := &input.Input{
+				Body: input.Body{
+					BodyType:      input.JSONBody,
+					RawJSONFields: []input.Field{{Name: "a", Value: tt.value}},
+				},
+			}
+			for _, format := range tt.formats {
+				if _, err := buildHTTPBody(in, format, ""); err == nil {
+					t.Errorf("error expected for %q in format %v", tt.value, format)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildHTTPBody_BinaryFormats(t *testing.T) {
+	body := input.Body{
+		BodyType: input.JSONBody,
+		Fields: []input.Field{
+			{Name: "foo", Value: "bar"},
+		},
+		RawJSONFields: []input.Field{
+			{Name: "int", Value: "42"},
+			{Name: "float", Value: "1.5"},
+			{Name: "array", Value: `[1, null, true]`},
+		},
+	}
+	testCases := []struct {
+		title               string
+		format              BodyFormat
+		unmarshal           func(data []byte) (map[string]interface{}, error)
+		expected            map[string]interface{}
+		expectedContentType string
+	}{
+		{
+			title:  "MessagePack",
+			format: MsgpackFormat,
+			unmarshal: func(data []byte) (map[string]interface{}, error) {
+				var v map[string]interface{}
+				err := msgpack.Unmarshal(data, &v)
+				return v, err
+			},
+			expected: map[string]interface{}{
+				"foo":   "bar",
+				"int":   int64(42),
+				"float": 1.5,
+				"array": []interface{}{int64(1), nil, true},
+			},
+			expectedContentType: "application/msgpack",
+		},
+		{
+			title:  "CBOR",
+			format: CBORFormat,
+			unmarshal: func(data []byte) (map[string]interface{}, error) {
+				var v map[string]interface{}
+				err := cbor.Unmarshal(data, &v)
+				return v, err
+			},
+			// CBOR decodes non-negative integers into uint64
+			expected: map[string]interface{}{
+				"foo":   "bar",
+				"int":   uint64(42),
+				"float": 1.5,
+				"array": []interface{}{uint64(1), nil, true},
+			},
+			expectedContentType: "application/cbor",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Exercise
+			bodyTuple, err := buildHTTPBody(&input.Input{Body: body}, tt.format, "")
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			actualBody := readAll(t, bodyTuple.body)
+			actual, err := tt.unmarshal([]byte(actualBody))
+			if err != nil {
+				t.Fatalf("failed to unmarshal body: err=%+v", err)
+			}
+			if !reflect.DeepEqual(tt.expected, actual) {
+				t.Errorf("unexpected body: expected=%#v, actual=%#v", tt.expected, actual)
+			}
+			if bodyTuple.contentType != tt.expectedContentType {
+				t.Errorf("unexpected content type: expected=%s, actual=%s", tt.expectedContentType, bodyTuple.contentType)
+			}
+			if bodyTuple.contentLength != int64(len(actualBody)) {
+				t.Errorf("invalid content length: len(body)=%v, actual=%v", len(actualBody), bodyTuple.contentLength)
+			}
+		})
+	}
+}
+
 func TestBuildHTTPBody_FormBody_URLEncoded(t *testing.T) {
 	// Setup
 	fileName := makeTempFile(t, "love & peace")
@@ -294,7 +425,7 @@ func TestBuildHTTPBody_FormBody_URLEncoded(t *testing.T) {
 	in := &input.Input{Body: body}
 
 	// Exercise
-	bodyTuple, err := buildHTTPBody(in)
+	bodyTuple, err := buildHTTPBody(in, JSONFormat, "")
 	if err != nil {
 		t.Fatalf("unexpected error: err=%+v", err)
 	}
@@ -332,7 +463,7 @@ func TestBuildHTTPBody_FormBody_Multipart(t *testing.T) {
 	in := &input.Input{Body: body}
 
 	// Exercise
-	bodyTuple, err := buildHTTPBody(in)
+	bodyTuple, err := buildHTTPBody(in, JSONFormat, "")
 	if err != nil {
 		t.Fatalf("unexpected error: err=%+v", err)
 	}
@@ -381,7 +512,7 @@ func TestBuildHTTPBody_RawBody(t *testing.T) {
 	in := &input.Input{Body: body}
 
 	// Exercise
-	bodyTuple, err := buildHTTPBody(in)
+	bodyTuple, err := buildHTTPBody(in, JSONFormat, "")
 	if err != nil {
 		t.Fatalf("unexpected error: err=%+v", err)
 	}
@@ -400,3 +531,153 @@ func TestBuildHTTPBody_RawBody(t *testing.T) {
 		t.Errorf("invalid content length: len(body)=%v, actual=%v", len(actualBody), bodyTuple.contentLength)
 	}
 }
+
+func TestBuildHTTPBody_Charset(t *testing.T) {
+	testCases := []struct {
+		title               string
+		body                input.Body
+		expectedBody        string
+		expectedContentType string
+	}{
+		{
+			title: "URL-encoded form",
+			body: input.Body{
+				BodyType: input.FormBody,
+				Fields: []input.Field{
+					{Name: "名前", Value: "こんにちは"},
+				},
+			},
+			expectedBody:        "%96%BC%91O=%82%B1%82%F1%82%C9%82%BF%82%CD",
+			expectedContentType: "application/x-www-form-urlencoded; charset=Shift_JIS",
+		},
+		{
+			title: "Raw body",
+			body: input.Body{
+				BodyType: input.RawBody,
+				Raw:      []byte("こんにちは"),
+			},
+			expectedBody:        "\x82\xb1\x82\xf1\x82\xc9\x82\xbf\x82\xcd",
+			expectedContentType: "application/json; charset=Shift_JIS",
+		},
+		{
+			title: "Raw body already encoded",
+			body: input.Body{
+				BodyType: input.RawBody,
+				Raw:      []byte("\x82\xb1\x82\xf1"),
+			},
+			expectedBody:        "\x82\xb1\x82\xf1",
+			expectedContentType: "application/json",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Exercise
+			bodyTuple, err := buildHTTPBody(&input.Input{Body: tt.body}, JSONFormat, "Shift_JIS")
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			actualBody := readAll(t, bodyTuple.body)
+			if actualBody != tt.expectedBody {
+				t.Errorf("unexpected body: expected=%q, actual=%q", tt.expectedBody, actualBody)
+			}
+			if bodyTuple.contentType != tt.expectedContentType {
+				t.Errorf("unexpected content type: expected=%s, actual=%s", tt.expectedContentType, bodyTuple.contentType)
+			}
+			if bodyTuple.contentLength != int64(len(actualBody)) {
+				t.Errorf("invalid content length: len(body)=%v, actual=%v", len(actualBody), bodyTuple.contentLength)
+			}
+		})
+	}
+}
+
+func TestBuildHTTPRequest_CharsetContentType(t *testing.T) {
+	testCases := []struct {
+		title               string
+		contentType         string
+		requestCharset      string
+		expectedContentType string
+	}{
+		{
+			title:               "Content-Type without charset",
+			contentType:         "text/plain",
+			requestCharset:      "Shift_JIS",
+			expectedContentType: "text/plain; charset=Shift_JIS",
+		},
+		{
+			title:               "Content-Type with another charset",
+			contentType:         "text/plain; charset=EUC-JP",
+			requestCharset:      "Shift_JIS",
+			expectedContentType: "text/plain; charset=Shift_JIS",
+		},
+		{
+			title:               "Content-Type with charset",
+			contentType:         "text/plain; charset=EUC-JP",
+			expectedContentType: "text/plain; charset=EUC-JP",
+		},
+		{
+			title:               "UTF-8",
+			contentType:         "text/plain",
+			expectedContentType: "text/plain",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			in := &input.Input{
+				Method: input.Method("POST"),
+				URL:    parseURL(t, "http://localhost/"),
+				Header: input.Header{
+					Fields: []input.Field{{Name: "Content-Type", Value: tt.contentType}},
+				},
+				Body: input.Body{BodyType: input.RawBody, Raw: []byte("こんにちは")},
+			}
+			actual, err := BuildHTTPRequest(in, &Options{RequestCharset: tt.requestCharset})
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if contentType := actual.Header.Get("Content-Type"); contentType != tt.expectedContentType {
+				t.Errorf("unexpected content type: expected=%s, actual=%s", tt.expectedContentType, contentType)
+			}
+		})
+	}
+}
+
+func TestBuildHTTPBody_CharsetUnencodable(t *testing.T) {
+	in := &input.Input{
+		Body: input.Body{
+			BodyType: input.FormBody,
+			Fields:   []input.Field{{Name: "sushi", Value: "🍣"}},
+		},
+	}
+	if _, err := buildHTTPBody(in, JSONFormat, "Shift_JIS"); err == nil {
+		t.Errorf("error expected")
+	}
+}
+
+func TestRequestCharset(t *testing.T) {
+	testCases := []struct {
+		title          string
+		contentType    string
+		requestCharset string
+		expected       string
+	}{
+		{title: "Default", expected: ""},
+		{title: "Content-Type", contentType: "text/plain; charset=EUC-JP", expected: "EUC-JP"},
+		{title: "--request-charset", contentType: "text/plain; charset=EUC-JP", requestCharset: "Shift_JIS", expected: "Shift_JIS"},
+		{title: "UTF-8", contentType: "text/plain; charset=UTF-8", expected: ""},
+		{title: "Unknown charset", contentType: "text/plain; charset=unknown", expected: ""},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			header := http.Header{}
+			if tt.contentType != "" {
+				header.Set("Content-Type", tt.contentType)
+			}
+			actual := requestCharset(header, &Options{RequestCharset: tt.requestCharset})
+			if actual != tt.expected {
+				t.Errorf("unexpected charset: expected=%q, actual=%q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package exchange
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// requestCharset returns the charset in which request bodies are encoded.
+// --request-charset takes precedence over the charset parameter of Content-Type.
+// It returns "" for UTF-8.
+func requestCharset(header http.Header, options *Options) string {
+	charset := options.RequestCharset
+	if charset == "" {
+		_, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+		if err != nil {
+			return ""
+		}
+		charset = params["charset"]
+	}
+	if lookupEncoding(charset) == nil {
+		return ""
+	}
+	return charset
+}
+
+// hasCharset reports whether contentType has the charset parameter.
+func hasCharset(contentType string) bool {
+	_, params, err := mime.ParseMediaType(contentType)
+	return err == nil && params["charset"] != ""
+}
+
+// setCharset sets the charset parameter of contentType to charset.
+// contentType is returned as it is if it cannot be parsed.
+func setCharset(contentType string, charset string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || strings.EqualFold(params["charset"], charset) {
+		return contentType
+	}
+	params["charset"] = charset
+	if formatted := mime.FormatMediaType(mediaType, params); formatted != "" {
+		return formatted
+	}
+	return contentType
+}
+
+// lookupEncoding returns the encoding named charset. It returns nil if charset
+// is UTF-8 or unknown.
+func lookupEncoding(charset string) encoding.Encoding {
+	if charset == "" {
+		return nil
+	}
+	e, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil
+	}
+	if name, _ := htmlindex.Name(e); name == "utf-8" {
+		return nil
+	}
+	return e
+}
+
+// encodeString converts s from UTF-8 into charset.
+func encodeString(s string, charset string) (string, error) {
+	e := lookupEncoding(charset)
+	if e == nil {
+		return s, nil
+	}
+	encoded, err := e.NewEncoder().String(s)
+	if err != nil {
+		return "", errors.Wrapf(err, "encoding '%s' in %s", s, charset)
+	}
+	return encoded, nil
+}
@@ -11,7 +11,7 @@ func BuildHTTPClient(options *Options) (*http.Client, error) {
 		return http.ErrUseLastResponse
 	}
 	if options.FollowRedirects {
-		checkRedirect = nil
+		checkRedirect = newCheckRedirect(options)
 	}
 
 	client := http.Client{
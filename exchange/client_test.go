@@ -0,0 +1,58 @@
+package exchange
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestBuildHTTPClient_OnRedirect(t *testing.T) {
+	// Setup
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/b", http.StatusFound)
+	})
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/c", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/c", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("done"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var hops []string
+	options := &Options{
+		FollowRedirects: true,
+		OnRedirect: func(response *http.Response, next *http.Request) error {
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil || len(body) == 0 {
+				t.Errorf("body of redirect response should be readable: body=%q, err=%+v", body, err)
+			}
+			hops = append(hops, response.Request.URL.Path+" -> "+next.URL.Path)
+			return nil
+		},
+	}
+
+	// Exercise
+	client, err := BuildHTTPClient(options)
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	resp, err := client.Get(server.URL + "/a")
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	defer resp.Body.Close()
+
+	// Verify
+	if resp.Request.URL.Path != "/c" {
+		t.Errorf("unexpected final path: %s", resp.Request.URL.Path)
+	}
+	expected := []string{"/a -> /b", "/b -> /c"}
+	if !reflect.DeepEqual(hops, expected) {
+		t.Errorf("unexpected hops: expected=%v, actual=%v", expected, hops)
+	}
+}
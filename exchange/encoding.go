@@ -0,0 +1,78 @@
+package exchange
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// BodyFormat is the serialization of bodies built from data items (key=value).
+type BodyFormat int
+
+const (
+	JSONFormat BodyFormat = iota
+	MsgpackFormat
+	CBORFormat
+)
+
+// marshalBody serializes obj in format. It returns the serialized body and its content type.
+func marshalBody(obj map[string]interface{}, format BodyFormat) ([]byte, string, error) {
+	switch format {
+	case JSONFormat:
+		body, err := json.Marshal(obj)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "marshaling JSON of HTTP body")
+		}
+		return body, "application/json", nil
+	case MsgpackFormat:
+		var buffer bytes.Buffer
+		encoder := msgpack.NewEncoder(&buffer)
+		encoder.SetSortMapKeys(true)
+		if err := encoder.Encode(fromJSONNumbers(obj)); err != nil {
+			return nil, "", errors.Wrap(err, "marshaling MessagePack of HTTP body")
+		}
+		return buffer.Bytes(), "application/msgpack", nil
+	case CBORFormat:
+		encMode, err := cbor.CanonicalEncOptions().EncMode()
+		if err != nil {
+			return nil, "", errors.Wrap(err, "marshaling CBOR of HTTP body")
+		}
+		body, err := encMode.Marshal(fromJSONNumbers(obj))
+		if err != nil {
+			return nil, "", errors.Wrap(err, "marshaling CBOR of HTTP body")
+		}
+		return body, "application/cbor", nil
+	default:
+		return nil, "", errors.Errorf("unknown body format: %v", format)
+	}
+}
+
+// fromJSONNumbers replaces json.Number in v with int64 (or float64 if the
+// number is not an integer) so that numbers are encoded as native ones.
+func fromJSONNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = fromJSONNumbers(value)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, value := range v {
+			a[i] = fromJSONNumbers(value)
+		}
+		return a
+	default:
+		return v
+	}
+}
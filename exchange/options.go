@@ -13,6 +13,25 @@ type Options struct {
 	ForceHTTP1      bool
 	CheckStatus     bool
 	Transport       http.RoundTripper
+
+	// Serialization of bodies built from data items. JSON is used by default.
+	BodyFormat BodyFormat
+
+	// Charset of form and raw request bodies. If empty, the charset in
+	// Content-Type is used (UTF-8 if absent).
+	RequestCharset string
+
+	// OnRedirect is called with a redirect response and the request to
+	// follow it before the request is sent. The body of response can be read
+	// in OnRedirect. Returning an error aborts the exchange. It is also called
+	// with the redirect that exceeds MaxRedirects, in which case next is not
+	// sent and the exchange fails with TooManyRedirectsError.
+	OnRedirect func(response *http.Response, next *http.Request) error
+
+	// Redirect policy applied if FollowRedirects is true
+	MaxRedirects   *int // DefaultMaxRedirects is used if nil
+	RedirectAuth   RedirectAuth
+	PreserveMethod bool // keep the method and the body on 301 and 302 redirects
 }
 
 type AuthOptions struct {
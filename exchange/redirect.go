@@ -0,0 +1,152 @@
+package exchange
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxRedirects is the default maximum number of redirects to follow.
+const DefaultMaxRedirects = 10
+
+// RedirectAuth is how credentials are handled on redirects to another origin.
+type RedirectAuth int
+
+const (
+	// RedirectAuthStrip removes credentials when the scheme, the host or the
+	// port differs from the initial request.
+	RedirectAuthStrip RedirectAuth = iota
+	// RedirectAuthKeep sends credentials to any origin.
+	RedirectAuthKeep
+)
+
+// credentialHeaders are the header fields regarded as credentials.
+var credentialHeaders = []string{"Authorization", "Cookie"}
+
+// bodyHeaders are the header fields that describe the request body. Go's
+// client removes them along with the body on 301, 302 and 303 redirects.
+var bodyHeaders = []string{"Content-Type", "Content-Encoding", "Content-Language"}
+
+// TooManyRedirectsError is returned when a redirect chain exceeds the maximum.
+type TooManyRedirectsError struct {
+	MaxRedirects int
+}
+
+func (e *TooManyRedirectsError) Error() string {
+	if e.MaxRedirects == 1 {
+		return "stopped after 1 redirect"
+	}
+	return fmt.Sprintf("stopped after %d redirects", e.MaxRedirects)
+}
+
+// IsTooManyRedirects reports whether err is caused by TooManyRedirectsError.
+func IsTooManyRedirects(err error) bool {
+	err = errors.Cause(err)
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	_, ok := err.(*TooManyRedirectsError)
+	return ok
+}
+
+// newCheckRedirect returns a CheckRedirect function of http.Client that applies
+// the redirect policy of options.
+func newCheckRedirect(options *Options) func(req *http.Request, via []*http.Request) error {
+	maxRedirects := DefaultMaxRedirects
+	if options.MaxRedirects != nil {
+		maxRedirects = *options.MaxRedirects
+	}
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			// The redirect response is still reported
+			if options.OnRedirect != nil {
+				if err := options.OnRedirect(req.Response, req); err != nil {
+					return err
+				}
+			}
+			return &TooManyRedirectsError{MaxRedirects: maxRedirects}
+		}
+		initial := via[0]
+		previous := via[len(via)-1]
+
+		if options.PreserveMethod {
+			if err := preserveMethod(req, previous, initial); err != nil {
+				return err
+			}
+		}
+
+		switch options.RedirectAuth {
+		case RedirectAuthStrip:
+			if !sameOrigin(initial.URL, req.URL) {
+				for _, name := range credentialHeaders {
+					req.Header.Del(name)
+				}
+			}
+		case RedirectAuthKeep:
+			// Go's client removes them on redirects to another domain
+			for _, name := range credentialHeaders {
+				if values, ok := initial.Header[name]; ok {
+					req.Header[name] = values
+				}
+			}
+		}
+
+		if options.OnRedirect != nil {
+			return options.OnRedirect(req.Response, req)
+		}
+		return nil
+	}
+}
+
+// preserveMethod makes req have the method of previous on 301 and 302
+// redirects, which Go's client changes to GET. The body of initial is sent
+// again since Go's client drops it once it is not redirected with.
+func preserveMethod(req *http.Request, previous *http.Request, initial *http.Request) error {
+	switch req.Response.StatusCode {
+	case http.StatusSeeOther:
+		return nil
+	case http.StatusMovedPermanently, http.StatusFound:
+		req.Method = previous.Method
+	}
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return nil
+	}
+	if req.Body != nil || initial.GetBody == nil {
+		return nil
+	}
+	body, err := initial.GetBody()
+	if err != nil {
+		return errors.Wrap(err, "rewinding request body for redirect")
+	}
+	req.Body = body
+	req.GetBody = initial.GetBody
+	req.ContentLength = initial.ContentLength
+	for _, name := range bodyHeaders {
+		if values, ok := initial.Header[name]; ok {
+			req.Header[name] = values
+		}
+	}
+	return nil
+}
+
+// sameOrigin reports whether a and b have the same scheme, host and port.
+func sameOrigin(a *url.URL, b *url.URL) bool {
+	return a.Scheme == b.Scheme && canonicalHost(a) == canonicalHost(b)
+}
+
+// canonicalHost returns the lowercase host of u with the default port of the scheme.
+func canonicalHost(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+	}
+	return strings.ToLower(u.Hostname()) + ":" + port
+}
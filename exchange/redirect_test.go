@@ -0,0 +1,231 @@
+package exchange
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBuildHTTPClient_MaxRedirects(t *testing.T) {
+	// Setup: /n redirects to /n-1 and /0 responds
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/0" {
+			w.Write([]byte("done"))
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		http.Redirect(w, r, "/"+strconv.Itoa(n-1), http.StatusFound)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		title         string
+		path          string
+		maxRedirects  *int
+		shouldBeError bool
+	}{
+		{title: "Within the limit", path: "/3", maxRedirects: intPtr(3)},
+		{title: "Over the limit", path: "/3", maxRedirects: intPtr(2), shouldBeError: true},
+		{title: "No redirects allowed", path: "/1", maxRedirects: intPtr(0), shouldBeError: true},
+		{title: "No redirects needed", path: "/0", maxRedirects: intPtr(0)},
+		{title: "Within the default limit", path: "/10"},
+		{title: "Over the default limit", path: "/11", shouldBeError: true},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			client, err := BuildHTTPClient(&Options{FollowRedirects: true, MaxRedirects: tt.maxRedirects})
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			resp, err := client.Get(server.URL + tt.path)
+			if tt.shouldBeError {
+				if !IsTooManyRedirects(err) {
+					t.Errorf("TooManyRedirectsError expected: err=%+v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestBuildHTTPClient_MaxRedirects_OnRedirect(t *testing.T) {
+	// Setup
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/b", http.StatusFound)
+	})
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/c", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var hops []string
+	client, err := BuildHTTPClient(&Options{
+		FollowRedirects: true,
+		MaxRedirects:    intPtr(1),
+		OnRedirect: func(response *http.Response, next *http.Request) error {
+			hops = append(hops, response.Request.URL.Path+" -> "+next.URL.Path)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Exercise
+	_, err = client.Get(server.URL + "/a")
+
+	// Verify
+	if !IsTooManyRedirects(err) {
+		t.Errorf("TooManyRedirectsError expected: err=%+v", err)
+	}
+	// The redirect over the limit is reported as well
+	expected := []string{"/a -> /b", "/b -> /c"}
+	if strings.Join(hops, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("unexpected hops: expected=%v, actual=%v", expected, hops)
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestBuildHTTPClient_PreserveMethod(t *testing.T) {
+	// Setup
+	var method, body, contentType string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/found", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/temporary", http.StatusFound)
+	})
+	mux.HandleFunc("/temporary", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/done", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/see-other", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/done", http.StatusSeeOther)
+	})
+	mux.HandleFunc("/done", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		method, body, contentType = r.Method, string(b), r.Header.Get("Content-Type")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testCases := []struct {
+		title               string
+		path                string
+		preserveMethod      bool
+		expectedMethod      string
+		expectedBody        string
+		expectedContentType string
+	}{
+		{
+			title:          "302 without the option",
+			path:           "/found",
+			expectedMethod: "GET",
+		},
+		{
+			title:               "302 followed by 307",
+			path:                "/found",
+			preserveMethod:      true,
+			expectedMethod:      "POST",
+			expectedBody:        `{"a":"b"}`,
+			expectedContentType: "application/json",
+		},
+		{
+			title:          "303 is always GET",
+			path:           "/see-other",
+			preserveMethod: true,
+			expectedMethod: "GET",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			client, err := BuildHTTPClient(&Options{
+				FollowRedirects: true,
+				PreserveMethod:  tt.preserveMethod,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			resp, err := client.Post(server.URL+tt.path, "application/json", strings.NewReader(`{"a":"b"}`))
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			resp.Body.Close()
+
+			if method != tt.expectedMethod || body != tt.expectedBody || contentType != tt.expectedContentType {
+				t.Errorf("unexpected request: expected=(%s, %q, %q), actual=(%s, %q, %q)",
+					tt.expectedMethod, tt.expectedBody, tt.expectedContentType, method, body, contentType)
+			}
+		})
+	}
+}
+
+func TestBuildHTTPClient_RedirectAuth(t *testing.T) {
+	// Setup: origin redirects to the same origin and then to another origin (another port)
+	var sameOriginAuth, otherOriginAuth, otherOriginCookie string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherOriginAuth = r.Header.Get("Authorization")
+		otherOriginCookie = r.Header.Get("Cookie")
+	}))
+	defer other.Close()
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			http.Redirect(w, r, "/next", http.StatusFound)
+			return
+		}
+		sameOriginAuth = r.Header.Get("Authorization")
+		http.Redirect(w, r, other.URL, http.StatusFound)
+	}))
+	defer origin.Close()
+
+	testCases := []struct {
+		title        string
+		redirectAuth RedirectAuth
+		expectedAuth string // sent to the other origin
+	}{
+		{title: "strip", redirectAuth: RedirectAuthStrip, expectedAuth: ""},
+		{title: "keep", redirectAuth: RedirectAuthKeep, expectedAuth: "Bearer secret"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			sameOriginAuth, otherOriginAuth, otherOriginCookie = "", "", ""
+			client, err := BuildHTTPClient(&Options{
+				FollowRedirects: true,
+				RedirectAuth:    tt.redirectAuth,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			req, err := http.NewRequest("GET", origin.URL+"/start", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			req.Header.Set("Authorization", "Bearer secret")
+			req.Header.Set("Cookie", "session=secret")
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			resp.Body.Close()
+
+			if sameOriginAuth != "Bearer secret" {
+				t.Errorf("Authorization should be sent to the same origin: actual=%q", sameOriginAuth)
+			}
+			if otherOriginAuth != tt.expectedAuth {
+				t.Errorf("unexpected Authorization to another origin: expected=%q, actual=%q", tt.expectedAuth, otherOriginAuth)
+			}
+			if (otherOriginCookie != "") != (tt.expectedAuth != "") {
+				t.Errorf("unexpected Cookie to another origin: actual=%q", otherOriginCookie)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package exchange
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Trace is when each phase of an exchange happens. If redirects are
+// followed, the phases of the last request are kept.
+type Trace struct {
+	DNSStart     time.Time
+	DNSDone      time.Time
+	ConnectStart time.Time
+	ConnectDone  time.Time
+	TLSStart     time.Time
+	TLSDone      time.Time
+	GotConn      time.Time
+	WroteRequest time.Time
+	FirstByte    time.Time
+
+	RemoteAddr string
+	Reused     bool // whether the connection was reused
+}
+
+// Tracer records the phases of an exchange to a Trace.
+type Tracer struct {
+	// Some callbacks are called from other goroutines (e.g. while dialing)
+	mu    sync.Mutex
+	trace Trace
+}
+
+// Trace returns a copy of the phases recorded so far.
+func (t *Tracer) Trace() Trace {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.trace
+}
+
+// WithTrace returns a shallow copy of request that records its phases to a new Tracer.
+func WithTrace(request *http.Request) (*http.Request, *Tracer) {
+	t := &Tracer{}
+	clientTrace := &httptrace.ClientTrace{
+		GetConn: func(hostPort string) {
+			// Forget the phases of the previous request
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.trace.DNSStart, t.trace.DNSDone = time.Time{}, time.Time{}
+			t.trace.ConnectStart, t.trace.ConnectDone = time.Time{}, time.Time{}
+			t.trace.TLSStart, t.trace.TLSDone = time.Time{}, time.Time{}
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			now := time.Now()
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.trace.DNSStart = now
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			now := time.Now()
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.trace.DNSDone = now
+		},
+		ConnectStart: func(network, addr string) {
+			now := time.Now()
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// Only the first attempt is recorded if multiple addresses are tried
+			if t.trace.ConnectStart.IsZero() {
+				t.trace.ConnectStart = now
+			}
+		},
+		ConnectDone: func(network, addr string, err error) {
+			now := time.Now()
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.trace.ConnectDone = now
+		},
+		TLSHandshakeStart: func() {
+			now := time.Now()
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.trace.TLSStart = now
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			now := time.Now()
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.trace.TLSDone = now
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			now := time.Now()
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.trace.GotConn = now
+			t.trace.RemoteAddr = info.Conn.RemoteAddr().String()
+			t.trace.Reused = info.Reused
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			now := time.Now()
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.trace.WroteRequest = now
+		},
+		GotFirstResponseByte: func() {
+			now := time.Now()
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.trace.FirstByte = now
+		},
+	}
+	return request.WithContext(httptrace.WithClientTrace(request.Context(), clientTrace)), t
+}
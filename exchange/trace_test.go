@@ -0,0 +1,45 @@
+package exchange
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithTrace(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+	request, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Exercise
+	request, tracer := WithTrace(request)
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	trace := tracer.Trace()
+
+	// Verify
+	if trace.ConnectStart.IsZero() || trace.ConnectDone.Before(trace.ConnectStart) {
+		t.Errorf("unexpected connect phase: start=%v, done=%v", trace.ConnectStart, trace.ConnectDone)
+	}
+	if trace.WroteRequest.IsZero() || trace.FirstByte.Before(trace.WroteRequest) {
+		t.Errorf("unexpected TTFB phase: wrote=%v, first byte=%v", trace.WroteRequest, trace.FirstByte)
+	}
+	if trace.RemoteAddr != server.Listener.Addr().String() {
+		t.Errorf("unexpected remote address: expected=%s, actual=%s", server.Listener.Addr(), trace.RemoteAddr)
+	}
+	if !trace.TLSStart.IsZero() {
+		t.Errorf("TLS phase should not be recorded for plain HTTP")
+	}
+}
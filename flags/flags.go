@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +16,8 @@ import (
 	"github.com/nojima/httpie-go/version"
 	"github.com/pborman/getopt"
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh/terminal"
+	"golang.org/x/text/encoding/htmlindex"
 )
 
 var reNumber = regexp.MustCompile(`^[0-9.]+$`)
@@ -32,28 +35,87 @@ type OptionSet struct {
 type terminalInfo struct {
 	stdinIsTerminal  bool
 	stdoutIsTerminal bool
+	colorTerm        string // value of $COLORTERM
+	term             string // value of $TERM
+	noColor          bool   // whether $NO_COLOR is set to a non-empty value
+	forceColor       string // value of $FORCE_COLOR, or "1" if $CLICOLOR_FORCE is set to other than "0"
+	width            int    // width of stdout, or 0 if unknown
+	height           int    // height of stdout, or 0 if unknown
+	pager            string // value of $PAGER
+	hyperlinks       bool   // whether the terminal supports OSC 8 hyperlinks
 }
 
 func Parse(args []string) ([]string, Usage, *OptionSet, error) {
+	width, height := terminalSize(os.Stdout)
+	forceColor, forced := os.LookupEnv("FORCE_COLOR")
+	if cliColorForce := os.Getenv("CLICOLOR_FORCE"); !forced && cliColorForce != "" && cliColorForce != "0" {
+		forceColor, forced = "1", true
+	}
+	if forced && forceColor == "" {
+		// An empty FORCE_COLOR also forces colors
+		forceColor = "1"
+	}
 	return parse(args, terminalInfo{
 		stdinIsTerminal:  isatty.IsTerminal(os.Stdin.Fd()),
 		stdoutIsTerminal: isatty.IsTerminal(os.Stdout.Fd()),
+		colorTerm:        os.Getenv("COLORTERM"),
+		term:             os.Getenv("TERM"),
+		noColor:          os.Getenv("NO_COLOR") != "",
+		forceColor:       forceColor,
+		width:            width,
+		height:           height,
+		pager:            os.Getenv("PAGER"),
+		hyperlinks:       detectHyperlinks(os.Getenv),
 	})
 }
 
+func terminalSize(file *os.File) (width int, height int) {
+	width, height, err := terminal.GetSize(int(file.Fd()))
+	if err != nil {
+		return 0, 0
+	}
+	return width, height
+}
+
 func parse(args []string, terminalInfo terminalInfo) ([]string, Usage, *OptionSet, error) {
 	inputOptions := input.Options{}
-	outputOptions := output.Options{}
+	outputOptions := output.Options{CertWarningDays: output.DefaultCertWarningDays}
 	exchangeOptions := exchange.Options{}
 	var ignoreStdin bool
 	var verifyFlag string
 	var verboseFlag bool
+	var maxRedirectsFlag int
 	var headersFlag bool
 	var bodyFlag bool
+	var metaFlag bool
 	printFlag := "\000" // "\000" is a special value that indicates user did not specified --print
+	historyPrintFlag := "\000"
 	timeout := "30s"
 	var authFlag string
 	var prettyFlag string
+	var colorFlag string
+	var styleFlag string
+	var formatOptionsFlag string
+	var filterFlag string
+	var viewFlag string
+	var limits output.JSONLimits
+	var alwaysTruncateFlag bool
+	var hexdumpFlag bool
+	var msgpackFlag bool
+	var cborFlag bool
+	var protoDescriptorFlag string
+	var protoMessageFlag string
+	var responseCharsetFlag string
+	var jwtKeyFlag string
+	var redirectAuthFlag string
+	var headerFilterFlag string
+	var printHeadersFlag string
+	var pagerFlag bool
+	var noPagerFlag bool
+	var columnsFlag string
+	var jqFlag string
+	var sortedFlag bool
+	var unsortedFlag bool
 	var versionFlag bool
 	var licenseFlag bool
 
@@ -64,21 +126,59 @@ func parse(args []string, terminalInfo terminalInfo) ([]string, Usage, *OptionSe
 	flagSet.SetParameters("[METHOD] URL [ITEM [ITEM ...]]")
 	flagSet.BoolVarLong(&inputOptions.JSON, "json", 'j', "data items are serialized as JSON (default)")
 	flagSet.BoolVarLong(&inputOptions.Form, "form", 'f', "data items are serialized as form fields")
-	flagSet.StringVarLong(&printFlag, "print", 'p', "specifies what the output should contain (HBhb)")
-	flagSet.BoolVarLong(&verboseFlag, "verbose", 'v', "print the request as well as the response. shortcut for --print=HBhb")
+	flagSet.BoolVarLong(&msgpackFlag, "msgpack", 0, "data items are serialized as MessagePack")
+	flagSet.BoolVarLong(&cborFlag, "cbor", 0, "data items are serialized as CBOR")
+	flagSet.StringVarLong(&printFlag, "print", 'p', "specifies what the output should contain (HBhbm)")
+	flagSet.StringVarLong(&historyPrintFlag, "history-print", 0, "what to print for intermediate requests and responses with --all (HBhb, same as --print by default)")
+	flagSet.BoolVarLong(&verboseFlag, "verbose", 'v', "print the request and the response with metadata. shortcut for --print=HBhbm")
 	flagSet.BoolVarLong(&headersFlag, "headers", 'h', "print only the request headers. shortcut for --print=h")
 	flagSet.BoolVarLong(&bodyFlag, "body", 'b', "print only response body. shourtcut for --print=b")
+	flagSet.BoolVarLong(&metaFlag, "meta", 0, "print response metadata (elapsed time, body size, etc.) in addition to --headers, --body, --verbose or --print. alone, shortcut for --print=m")
+	flagSet.BoolVarLong(&outputOptions.PrintTiming, "timing", 0, "print the time of DNS lookup, TCP connect, TLS handshake, time to first byte and content transfer after the response")
 	flagSet.BoolVarLong(&ignoreStdin, "ignore-stdin", 0, "do not attempt to read stdin")
 	flagSet.BoolVarLong(&outputOptions.Download, "download", 'd', "download file")
 	flagSet.BoolVarLong(&outputOptions.Overwrite, "overwrite", 0, "overwrite existing file")
+	flagSet.BoolVarLong(&outputOptions.Continue, "continue", 'c', "resume a partial download of --output file (requires --download and --output). its ETag or Last-Modified is kept in <file>.resume until the download completes")
 	flagSet.BoolVarLong(&exchangeOptions.ForceHTTP1, "http1", 0, "force HTTP/1.1 protocol")
 	flagSet.StringVarLong(&outputOptions.OutputFile, "output", 'o', "output file")
 	flagSet.StringVarLong(&verifyFlag, "verify", 0, "verify Host SSL certificate, 'yes' or 'no' ('yes' by default, uppercase is also working)")
+	flagSet.BoolVarLong(&outputOptions.PrintTLS, "tls-info", 0, "print the TLS version, cipher suite, ALPN protocol and the certificate chain of the server after the response headers")
+	flagSet.IntVarLong(&outputOptions.CertWarningDays, "cert-warning", 0, "with --tls-info, warn about certificates expiring within this number of days (default 30, 0 disables)")
 	flagSet.StringVarLong(&timeout, "timeout", 0, "timeout seconds that you allow the whole operation to take")
 	flagSet.BoolVarLong(&exchangeOptions.CheckStatus, "check-status", 0, "Also check the HTTP status code and exit with an error if the status indicates one")
+	flagSet.BoolVarLong(&outputOptions.ProblemSummary, "problem-summary", 0, "with --check-status, print a summary of Problem Details (RFC 7807) response to stderr")
 	flagSet.StringVarLong(&authFlag, "auth", 'a', "colon-separated username and password for authentication")
 	flagSet.StringVarLong(&prettyFlag, "pretty", 0, "controls output formatting (all, format, none)")
+	flagSet.StringVarLong(&colorFlag, "color", 0, "when to use colors (always, auto, never); auto respects NO_COLOR and FORCE_COLOR")
+	flagSet.StringVarLong(&styleFlag, "style", 's', "output coloring style ("+strings.Join(output.ThemeNames(), ", ")+", or path to a theme file)")
+	flagSet.StringVarLong(&formatOptionsFlag, "format-options", 0, "controls output formatting details (e.g. json.indent:2,json.sort_keys:true,headers.sort:false)")
+	flagSet.BoolVarLong(&sortedFlag, "sorted", 0, "sort JSON keys and headers. shortcut for --format-options=json.sort_keys:true,headers.sort:true")
+	flagSet.BoolVarLong(&unsortedFlag, "unsorted", 0, "do not sort JSON keys and headers. shortcut for --format-options=json.sort_keys:false,headers.sort:false")
+	flagSet.StringVarLong(&filterFlag, "filter", 0, "select values from JSON response body by a jq-like expression (e.g. '.items[] | .name') or JSONPath (e.g. '$.items[*].name')")
+	flagSet.StringVarLong(&jqFlag, "jq", 0, "alias of --filter")
+	flagSet.StringVarLong(&viewFlag, "view", 0, "how to render JSON bodies (json, table)")
+	flagSet.StringVarLong(&columnsFlag, "columns", 0, "comma-separated columns of --view=table (implies --view=table)")
+	flagSet.IntVarLong(&limits.MaxDepth, "max-depth", 0, "collapse JSON arrays and objects nested deeper than this (terminal only)")
+	flagSet.IntVarLong(&limits.MaxArrayItems, "max-items", 0, "elide JSON array items after this number (terminal only)")
+	flagSet.IntVarLong(&limits.MaxStringLength, "max-string", 0, "elide characters of JSON strings after this length (terminal only)")
+	flagSet.BoolVarLong(&alwaysTruncateFlag, "always-truncate", 0, "apply --max-depth, --max-items and --max-string even if stdout is not a terminal")
+	flagSet.StringVarLong(&exchangeOptions.RequestCharset, "request-charset", 0, "encode form and raw request bodies in this charset (e.g. Shift_JIS)")
+	flagSet.StringVarLong(&responseCharsetFlag, "response-charset", 0, "override the charset of response bodies (e.g. Shift_JIS)")
+	flagSet.StringVarLong(&protoDescriptorFlag, "proto-descriptor", 0, "FileDescriptorSet file used to decode protobuf responses")
+	flagSet.StringVarLong(&protoMessageFlag, "proto-message", 0, "message name of protobuf responses (e.g. pkg.Msg)")
+	flagSet.StringVarLong(&headerFilterFlag, "header-filter", 0, "comma-separated glob patterns of headers to print; '!' excludes (e.g. 'Content-*,!X-Amzn-*')")
+	flagSet.StringVarLong(&printHeadersFlag, "print-headers", 0, "alias of --header-filter")
+	flagSet.BoolVarLong(&outputOptions.AnnotateHeaders, "annotate", 0, "explain well-known headers (dates, sizes, Cache-Control, Set-Cookie and Link) inline")
+	flagSet.BoolVarLong(&outputOptions.DecodeJWT, "jwt", 0, "print decoded JWTs found in Authorization, cookies and JSON bodies beneath the headers")
+	flagSet.StringVarLong(&jwtKeyFlag, "jwt-key", 0, "verify JWT signatures with a PEM public key or an HMAC secret in this file (implies --jwt)")
+	flagSet.BoolVarLong(&pagerFlag, "pager", 0, "pipe output through $PAGER (or '"+output.DefaultPager+"') even if it fits in the terminal")
+	flagSet.BoolVarLong(&noPagerFlag, "no-pager", 0, "do not pipe output through a pager when it does not fit in the terminal")
+	flagSet.BoolVarLong(&hexdumpFlag, "hexdump", 0, "print binary bodies in hexdump instead of a notice")
 	flagSet.BoolVarLong(&exchangeOptions.FollowRedirects, "follow", 'F', "follow 30x Location redirects")
+	maxRedirectsOption := flagSet.IntVarLong(&maxRedirectsFlag, "max-redirects", 0, "maximum number of redirects to follow with --follow (default 10). exits with status 6 if exceeded")
+	flagSet.StringVarLong(&redirectAuthFlag, "redirect-auth", 0, "credentials (Authorization and Cookie) on redirects to another origin: 'strip' (default) or 'keep'")
+	flagSet.BoolVarLong(&exchangeOptions.PreserveMethod, "preserve-method", 0, "keep the method and the body on 301 and 302 redirects as 307 and 308 do")
+	flagSet.BoolVarLong(&outputOptions.PrintAll, "all", 0, "with --follow, print intermediate requests and responses of redirects as well")
 	flagSet.BoolVarLong(&versionFlag, "version", 0, "print version and exit")
 	flagSet.BoolVarLong(&licenseFlag, "license", 0, "print license information and exit")
 	flagSet.Parse(args)
@@ -106,11 +206,32 @@ func parse(args []string, terminalInfo terminalInfo) ([]string, Usage, *OptionSe
 		verboseFlag,
 		headersFlag,
 		bodyFlag,
+		metaFlag,
 		terminalInfo.stdoutIsTerminal,
 		&outputOptions,
 	); err != nil {
 		return nil, nil, nil, err
 	}
+	if err := parseHistoryPrintFlag(historyPrintFlag, &outputOptions); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Parse --msgpack and --cbor
+	if msgpackFlag || cborFlag {
+		if inputOptions.JSON || inputOptions.Form || (msgpackFlag && cborFlag) {
+			return nil, nil, nil, errors.New("You can specify only one of --json, --form, --msgpack and --cbor")
+		}
+		if msgpackFlag {
+			exchangeOptions.BodyFormat = exchange.MsgpackFormat
+		} else {
+			exchangeOptions.BodyFormat = exchange.CBORFormat
+		}
+	}
+
+	// Check --continue
+	if outputOptions.Continue && (!outputOptions.Download || outputOptions.OutputFile == "") {
+		return nil, nil, nil, errors.New("--continue requires --download and --output")
+	}
 
 	// Parse --timeout
 	d, err := parseDurationOrSeconds(timeout)
@@ -123,10 +244,149 @@ func parse(args []string, terminalInfo terminalInfo) ([]string, Usage, *OptionSe
 	}
 	exchangeOptions.Timeout = d
 
-	// Parse --pretty
+	// Parse --max-redirects and --redirect-auth
+	if maxRedirectsOption.Seen() {
+		if maxRedirectsFlag < 0 {
+			return nil, nil, nil, errors.Errorf("--max-redirects must not be negative: %d", maxRedirectsFlag)
+		}
+		exchangeOptions.MaxRedirects = &maxRedirectsFlag
+	}
+	if err := parseRedirectAuth(redirectAuthFlag, &exchangeOptions); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Parse --pretty and --color
 	if err := parsePretty(prettyFlag, terminalInfo.stdoutIsTerminal, &outputOptions); err != nil {
 		return nil, nil, nil, err
 	}
+	if err := parseColor(colorFlag, prettyFlag, terminalInfo, &outputOptions); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Parse --style
+	if styleFlag != "" {
+		theme, err := output.LoadTheme(styleFlag)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		outputOptions.Theme = theme
+	}
+	outputOptions.ColorDepth = detectColorDepth(terminalInfo)
+	outputOptions.Hyperlinks = outputOptions.EnableColor && terminalInfo.hyperlinks
+
+	// Parse --format-options
+	if err := parseFormatOptions(formatOptionsFlag, sortedFlag, unsortedFlag, &outputOptions); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Parse --filter
+	if filterFlag != "" && jqFlag != "" {
+		return nil, nil, nil, errors.New("You cannot specify both of --filter and --jq")
+	}
+	if filterFlag == "" {
+		filterFlag = jqFlag
+	}
+	if filterFlag != "" {
+		filter, err := output.ParseFilter(filterFlag)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		outputOptions.Filter = filter
+	}
+
+	// Parse --header-filter
+	if headerFilterFlag != "" && printHeadersFlag != "" {
+		return nil, nil, nil, errors.New("You cannot specify both of --header-filter and --print-headers")
+	}
+	if headerFilterFlag == "" {
+		headerFilterFlag = printHeadersFlag
+	}
+	if headerFilterFlag != "" {
+		headerFilter, err := output.ParseHeaderFilter(headerFilterFlag)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		outputOptions.HeaderFilter = headerFilter
+	}
+
+	// Parse --view and --columns
+	if err := parseView(viewFlag, columnsFlag, &outputOptions); err != nil {
+		return nil, nil, nil, err
+	}
+	outputOptions.TerminalWidth = terminalInfo.width
+	outputOptions.TerminalHeight = terminalInfo.height
+
+	// Parse --pager and --no-pager
+	if pagerFlag && noPagerFlag {
+		return nil, nil, nil, errors.New("You cannot specify both of --pager and --no-pager")
+	}
+	// The pager is used only for terminals, so options decided by terminalInfo are kept as they are.
+	if terminalInfo.stdoutIsTerminal && !noPagerFlag && !outputOptions.Download {
+		outputOptions.Pager = terminalInfo.pager
+		if outputOptions.Pager == "" {
+			outputOptions.Pager = output.DefaultPager
+		}
+		outputOptions.PagerAlways = pagerFlag
+	}
+
+	// Parse --max-depth, --max-items and --max-string
+	if limits.MaxDepth < 0 || limits.MaxArrayItems < 0 || limits.MaxStringLength < 0 {
+		return nil, nil, nil, errors.New("--max-depth, --max-items and --max-string must not be negative")
+	}
+	// Truncated output must not be fed to other programs unless explicitly requested.
+	if terminalInfo.stdoutIsTerminal || alwaysTruncateFlag {
+		outputOptions.Limits = limits
+	}
+
+	// Parse --hexdump
+	if hexdumpFlag {
+		outputOptions.Binary = output.BinaryHexdump
+	} else if terminalInfo.stdoutIsTerminal {
+		// Binary data may mess up the terminal
+		outputOptions.Binary = output.BinaryNotice
+	}
+
+	// Parse --proto-descriptor and --proto-message
+	if protoDescriptorFlag != "" || protoMessageFlag != "" {
+		if protoDescriptorFlag == "" || protoMessageFlag == "" {
+			return nil, nil, nil, errors.New("--proto-descriptor and --proto-message must be specified together")
+		}
+		schema, err := output.LoadProtoSchema(protoDescriptorFlag, protoMessageFlag)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		outputOptions.ProtoSchema = schema
+	}
+
+	// Check --cert-warning
+	if outputOptions.CertWarningDays < 0 {
+		return nil, nil, nil, errors.Errorf("--cert-warning must not be negative: %d", outputOptions.CertWarningDays)
+	}
+
+	// Parse --jwt-key
+	if jwtKeyFlag != "" {
+		key, err := output.LoadJWTKey(jwtKeyFlag)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		outputOptions.DecodeJWT = true
+		outputOptions.JWTKey = key
+	}
+
+	// Parse --request-charset
+	if exchangeOptions.RequestCharset != "" {
+		if _, err := htmlindex.Get(exchangeOptions.RequestCharset); err != nil {
+			return nil, nil, nil, errors.Errorf("unknown charset: %s", exchangeOptions.RequestCharset)
+		}
+	}
+
+	// Parse --response-charset
+	if responseCharsetFlag != "" {
+		if _, err := htmlindex.Get(responseCharsetFlag); err != nil {
+			return nil, nil, nil, errors.Errorf("unknown charset: %s", responseCharsetFlag)
+		}
+		outputOptions.ResponseCharset = responseCharsetFlag
+	}
 
 	// Verify SSL
 	verifyFlag = strings.ToLower(verifyFlag)
@@ -170,6 +430,7 @@ func parsePrintFlag(
 	verboseFlag bool,
 	headersFlag bool,
 	bodyFlag bool,
+	metaFlag bool,
 	stdoutIsTerminal bool,
 	outputOptions *output.Options,
 ) error {
@@ -183,6 +444,9 @@ func parsePrintFlag(
 			outputOptions.PrintRequestHeader = true
 			outputOptions.PrintResponseHeader = true
 			outputOptions.PrintResponseBody = true
+			outputOptions.PrintMeta = true
+		} else if metaFlag {
+			outputOptions.PrintMeta = true
 		} else if stdoutIsTerminal {
 			outputOptions.PrintResponseHeader = true
 			outputOptions.PrintResponseBody = true
@@ -200,11 +464,60 @@ func parsePrintFlag(
 				outputOptions.PrintResponseHeader = true
 			case 'b':
 				outputOptions.PrintResponseBody = true
+			case 'm':
+				outputOptions.PrintMeta = true
 			default:
-				return errors.Errorf("invalid char in --print value (must be consist of HBhb): %c", c)
+				return errors.Errorf("invalid char in --print value (must be consist of HBhbm): %c", c)
 			}
 		}
 	}
+	// --meta is added to what the other flags print
+	if metaFlag {
+		outputOptions.PrintMeta = true
+	}
+	return nil
+}
+
+// parseHistoryPrintFlag parses --history-print. What is printed for the
+// final exchange is used by default.
+func parseHistoryPrintFlag(historyPrintFlag string, outputOptions *output.Options) error {
+	if historyPrintFlag == "\000" { // --history-print is not specified
+		outputOptions.History = output.HistoryOptions{
+			PrintRequestHeader:  outputOptions.PrintRequestHeader,
+			PrintRequestBody:    outputOptions.PrintRequestBody,
+			PrintResponseHeader: outputOptions.PrintResponseHeader,
+			PrintResponseBody:   outputOptions.PrintResponseBody,
+		}
+		return nil
+	}
+	history := output.HistoryOptions{}
+	for _, c := range historyPrintFlag {
+		switch c {
+		case 'H':
+			history.PrintRequestHeader = true
+		case 'B':
+			history.PrintRequestBody = true
+		case 'h':
+			history.PrintResponseHeader = true
+		case 'b':
+			history.PrintResponseBody = true
+		default:
+			return errors.Errorf("invalid char in --history-print value (must be consist of HBhb): %c", c)
+		}
+	}
+	outputOptions.History = history
+	return nil
+}
+
+func parseRedirectAuth(redirectAuthFlag string, exchangeOptions *exchange.Options) error {
+	switch strings.ToLower(redirectAuthFlag) {
+	case "", "strip":
+		exchangeOptions.RedirectAuth = exchange.RedirectAuthStrip
+	case "keep":
+		exchangeOptions.RedirectAuth = exchange.RedirectAuthKeep
+	default:
+		return errors.Errorf("unknown value of --redirect-auth: %s", redirectAuthFlag)
+	}
 	return nil
 }
 
@@ -230,6 +543,126 @@ func parsePretty(prettyFlag string, stdoutIsTerminal bool, outputOptions *output
 	return nil
 }
 
+// parseColor applies --color on top of --pretty. Unless --color=always or
+// --color=never is given, colors follow --pretty if specified, and otherwise
+// NO_COLOR and FORCE_COLOR are respected before checking whether stdout is a
+// terminal.
+func parseColor(colorFlag string, prettyFlag string, terminalInfo terminalInfo, outputOptions *output.Options) error {
+	enableColor := outputOptions.EnableColor
+	switch colorFlag {
+	case "always":
+		if prettyFlag == "none" {
+			// The plain printer cannot colorize output
+			return errors.New("--color=always cannot be used with --pretty=none")
+		}
+		enableColor = true
+	case "never":
+		enableColor = false
+	case "", "auto":
+		if prettyFlag != "" {
+			return nil
+		}
+		switch {
+		case terminalInfo.noColor:
+			enableColor = false
+		case terminalInfo.forceColor != "":
+			enableColor = terminalInfo.forceColor != "0" && terminalInfo.forceColor != "false"
+		case terminalInfo.term == "dumb":
+			enableColor = false
+		}
+	default:
+		return errors.Errorf("unknown value of --color: %s", colorFlag)
+	}
+
+	outputOptions.EnableColor = enableColor
+	if enableColor && prettyFlag == "" {
+		// Colors are applied to formatted output
+		outputOptions.EnableFormat = true
+	}
+	return nil
+}
+
+func detectColorDepth(terminalInfo terminalInfo) output.ColorDepth {
+	colorTerm := strings.ToLower(terminalInfo.colorTerm)
+	term := strings.ToLower(terminalInfo.term)
+	switch {
+	case terminalInfo.forceColor == "3":
+		return output.ColorDepthTrue
+	case terminalInfo.forceColor == "2":
+		return output.ColorDepth256
+	case colorTerm == "truecolor" || colorTerm == "24bit" || strings.HasSuffix(term, "-direct"):
+		return output.ColorDepthTrue
+	case strings.Contains(colorTerm, "256") || strings.Contains(term, "256"):
+		return output.ColorDepth256
+	default:
+		return output.ColorDepth8
+	}
+}
+
+func parseView(viewFlag string, columnsFlag string, outputOptions *output.Options) error {
+	if columnsFlag != "" {
+		for _, column := range strings.Split(columnsFlag, ",") {
+			outputOptions.Columns = append(outputOptions.Columns, strings.TrimSpace(column))
+		}
+		if viewFlag == "" {
+			viewFlag = "table"
+		}
+	}
+
+	switch viewFlag {
+	case "", "json":
+		outputOptions.View = output.JSONView
+	case "table":
+		outputOptions.View = output.TableView
+	default:
+		return errors.Errorf("unknown value of --view (must be json or table): %s", viewFlag)
+	}
+	return nil
+}
+
+func parseFormatOptions(formatOptionsFlag string, sortedFlag bool, unsortedFlag bool, outputOptions *output.Options) error {
+	format := output.DefaultFormatOptions
+
+	if sortedFlag && unsortedFlag {
+		return errors.New("You cannot specify both of --sorted and --unsorted")
+	}
+	if sortedFlag || unsortedFlag {
+		format.JSONSortKeys = sortedFlag
+		format.HeadersSort = sortedFlag
+	}
+
+	if formatOptionsFlag != "" {
+		for _, option := range strings.Split(formatOptionsFlag, ",") {
+			colonIndex := strings.Index(option, ":")
+			if colonIndex == -1 {
+				return errors.Errorf("invalid --format-options (must be in the form of key:value): %s", option)
+			}
+			key := strings.TrimSpace(option[:colonIndex])
+			value := strings.TrimSpace(option[colonIndex+1:])
+			var err error
+			switch key {
+			case "json.indent":
+				format.JSONIndent, err = strconv.Atoi(value)
+				if err == nil && format.JSONIndent < 0 {
+					err = errors.New("must not be negative")
+				}
+			case "json.sort_keys":
+				format.JSONSortKeys, err = strconv.ParseBool(value)
+			case "headers.sort":
+				format.HeadersSort, err = strconv.ParseBool(value)
+			default:
+				return errors.Errorf("unknown key in --format-options (must be one of json.indent, json.sort_keys, headers.sort): %s", key)
+			}
+			if err != nil {
+				return errors.Errorf("invalid value of %s in --format-options: %s", key, value)
+			}
+		}
+	}
+
+	outputOptions.Format = &format
+	return nil
+}
+
 func parseDurationOrSeconds(timeout string) (time.Duration, error) {
 	if reNumber.MatchString(timeout) {
 		timeout += "s"
@@ -249,3 +682,23 @@ func parseAuth(authFlag string) (string, *string) {
 	password := authFlag[colonIndex+1:]
 	return authFlag[:colonIndex], &password
 }
+
+// detectHyperlinks reports whether the terminal described by environment
+// variables supports OSC 8 hyperlinks. FORCE_HYPERLINK overrides the detection.
+func detectHyperlinks(getenv func(string) string) bool {
+	if force := getenv("FORCE_HYPERLINK"); force != "" {
+		return force != "0"
+	}
+	if getenv("WT_SESSION") != "" || getenv("KITTY_WINDOW_ID") != "" || getenv("DOMTERM") != "" {
+		return true
+	}
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty":
+		return true
+	}
+	// VTE-based terminals (e.g. GNOME Terminal) support them since 0.50
+	if version, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && version >= 5000 {
+		return true
+	}
+	return false
+}
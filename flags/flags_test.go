@@ -31,6 +31,14 @@ func TestParse(t *testing.T) {
 			PrintResponseBody:   true,
 			EnableColor:         true,
 			EnableFormat:        true,
+			Format:              &output.DefaultFormatOptions,
+			Binary:              output.BinaryNotice,
+			Pager:               output.DefaultPager,
+			CertWarningDays:     output.DefaultCertWarningDays,
+			History: output.HistoryOptions{
+				PrintResponseHeader: true,
+				PrintResponseBody:   true,
+			},
 		},
 	}
 	if !reflect.DeepEqual(expectedOptionSet, optionSet) {
@@ -38,6 +46,143 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParse_Limits(t *testing.T) {
+	testCases := []struct {
+		title            string
+		args             []string
+		stdoutIsTerminal bool
+		expected         output.JSONLimits
+	}{
+		{
+			title:            "Terminal",
+			args:             []string{"ht", "--max-depth=2", "--max-items=10", "--max-string=80"},
+			stdoutIsTerminal: true,
+			expected:         output.JSONLimits{MaxDepth: 2, MaxArrayItems: 10, MaxStringLength: 80},
+		},
+		{
+			title:            "Piped",
+			args:             []string{"ht", "--max-depth=2", "--max-items=10", "--max-string=80"},
+			stdoutIsTerminal: false,
+			expected:         output.JSONLimits{},
+		},
+		{
+			title:            "Piped with --always-truncate",
+			args:             []string{"ht", "--max-items=10", "--always-truncate"},
+			stdoutIsTerminal: false,
+			expected:         output.JSONLimits{MaxArrayItems: 10},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			_, _, optionSet, err := parse(tt.args, terminalInfo{
+				stdinIsTerminal:  true,
+				stdoutIsTerminal: tt.stdoutIsTerminal,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if optionSet.OutputOptions.Limits != tt.expected {
+				t.Errorf("unexpected limits: expected=%+v, actual=%+v", tt.expected, optionSet.OutputOptions.Limits)
+			}
+		})
+	}
+}
+
+func TestParse_MaxRedirects(t *testing.T) {
+	testCases := []struct {
+		title         string
+		args          []string
+		expected      *int
+		shouldBeError bool
+	}{
+		{title: "Default", args: []string{"ht"}},
+		{title: "Specified", args: []string{"ht", "--max-redirects=3"}, expected: intPtr(3)},
+		{title: "No redirects", args: []string{"ht", "--max-redirects=0"}, expected: intPtr(0)},
+		{title: "Negative", args: []string{"ht", "--max-redirects=-1"}, shouldBeError: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			_, _, optionSet, err := parse(tt.args, terminalInfo{stdinIsTerminal: true})
+			if tt.shouldBeError {
+				if err == nil {
+					t.Errorf("error expected but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if !reflect.DeepEqual(optionSet.ExchangeOptions.MaxRedirects, tt.expected) {
+				t.Errorf("unexpected max redirects: expected=%v, actual=%v", tt.expected, optionSet.ExchangeOptions.MaxRedirects)
+			}
+		})
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestParse_Pager(t *testing.T) {
+	testCases := []struct {
+		title            string
+		args             []string
+		stdoutIsTerminal bool
+		pagerEnv         string
+		expectedPager    string
+		expectedAlways   bool
+	}{
+		{
+			title:            "Terminal",
+			args:             []string{"ht"},
+			stdoutIsTerminal: true,
+			expectedPager:    output.DefaultPager,
+		},
+		{
+			title:            "Terminal with $PAGER",
+			args:             []string{"ht", "--pager"},
+			stdoutIsTerminal: true,
+			pagerEnv:         "more",
+			expectedPager:    "more",
+			expectedAlways:   true,
+		},
+		{
+			title:            "Terminal with --no-pager",
+			args:             []string{"ht", "--no-pager"},
+			stdoutIsTerminal: true,
+		},
+		{
+			title:            "Piped",
+			args:             []string{"ht", "--pager"},
+			stdoutIsTerminal: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			_, _, optionSet, err := parse(tt.args, terminalInfo{
+				stdinIsTerminal:  true,
+				stdoutIsTerminal: tt.stdoutIsTerminal,
+				pager:            tt.pagerEnv,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			options := optionSet.OutputOptions
+			if options.Pager != tt.expectedPager || options.PagerAlways != tt.expectedAlways {
+				t.Errorf("unexpected pager: expected=(%q, %v), actual=(%q, %v)",
+					tt.expectedPager, tt.expectedAlways, options.Pager, options.PagerAlways)
+			}
+			// Decisions based on the terminal are not affected by the pager
+			if options.EnableColor != tt.stdoutIsTerminal {
+				t.Errorf("unexpected EnableColor: %v", options.EnableColor)
+			}
+		})
+	}
+}
+
 func TestParsePrintFlag(t *testing.T) {
 	noPrintFlag := "\000"
 	testCases := []struct {
@@ -46,11 +191,13 @@ func TestParsePrintFlag(t *testing.T) {
 		verboseFlag                 bool
 		headersFlag                 bool
 		bodyFlag                    bool
+		metaFlag                    bool
 		stdoutIsTerminal            bool
 		expectedPrintRequestHeader  bool
 		expectedPrintRequestBody    bool
 		expectedPrintResponseHeader bool
 		expectedPrintResponseBody   bool
+		expectedPrintMeta           bool
 	}{
 		{
 			title:                       "No flags specified (stdout is terminal)",
@@ -117,6 +264,53 @@ func TestParsePrintFlag(t *testing.T) {
 			expectedPrintRequestBody:    true,
 			expectedPrintResponseHeader: true,
 			expectedPrintResponseBody:   true,
+			expectedPrintMeta:           true,
+		},
+		{
+			title:             "--meta",
+			printFlag:         noPrintFlag,
+			metaFlag:          true,
+			expectedPrintMeta: true,
+		},
+		{
+			title:                       "--headers --meta",
+			printFlag:                   noPrintFlag,
+			headersFlag:                 true,
+			metaFlag:                    true,
+			expectedPrintResponseHeader: true,
+			expectedPrintMeta:           true,
+		},
+		{
+			title:                     "--body --meta",
+			printFlag:                 noPrintFlag,
+			bodyFlag:                  true,
+			metaFlag:                  true,
+			expectedPrintResponseBody: true,
+			expectedPrintMeta:         true,
+		},
+		{
+			title:                       "--verbose --meta",
+			printFlag:                   noPrintFlag,
+			verboseFlag:                 true,
+			metaFlag:                    true,
+			expectedPrintRequestHeader:  true,
+			expectedPrintRequestBody:    true,
+			expectedPrintResponseHeader: true,
+			expectedPrintResponseBody:   true,
+			expectedPrintMeta:           true,
+		},
+		{
+			title:                       "--print=h --meta",
+			printFlag:                   "h",
+			metaFlag:                    true,
+			expectedPrintResponseHeader: true,
+			expectedPrintMeta:           true,
+		},
+		{
+			title:                     `--print=bm`,
+			printFlag:                 "bm",
+			expectedPrintResponseBody: true,
+			expectedPrintMeta:         true,
 		},
 	}
 
@@ -128,6 +322,7 @@ func TestParsePrintFlag(t *testing.T) {
 				tt.verboseFlag,
 				tt.headersFlag,
 				tt.bodyFlag,
+				tt.metaFlag,
 				tt.stdoutIsTerminal,
 				&options,
 			); err != nil {
@@ -150,6 +345,389 @@ func TestParsePrintFlag(t *testing.T) {
 				t.Errorf("unexpected PrintResponseBody: expected=%v, actual=%v",
 					tt.expectedPrintResponseBody, options.PrintResponseBody)
 			}
+			if options.PrintMeta != tt.expectedPrintMeta {
+				t.Errorf("unexpected PrintMeta: expected=%v, actual=%v",
+					tt.expectedPrintMeta, options.PrintMeta)
+			}
+		})
+	}
+}
+
+func TestParseHistoryPrintFlag(t *testing.T) {
+	testCases := []struct {
+		title            string
+		historyPrintFlag string
+		options          output.Options
+		expected         output.HistoryOptions
+	}{
+		{
+			title:            "Same as --print by default",
+			historyPrintFlag: "\000",
+			options:          output.Options{PrintRequestHeader: true, PrintResponseHeader: true, PrintResponseBody: true},
+			expected:         output.HistoryOptions{PrintRequestHeader: true, PrintResponseHeader: true, PrintResponseBody: true},
+		},
+		{
+			title:            `--history-print=""`,
+			historyPrintFlag: "",
+			options:          output.Options{PrintResponseHeader: true, PrintResponseBody: true},
+			expected:         output.HistoryOptions{},
+		},
+		{
+			title:            `--history-print=Hh`,
+			historyPrintFlag: "Hh",
+			options:          output.Options{PrintResponseBody: true},
+			expected:         output.HistoryOptions{PrintRequestHeader: true, PrintResponseHeader: true},
+		},
+		{
+			title:            `--history-print=HBhb`,
+			historyPrintFlag: "HBhb",
+			expected: output.HistoryOptions{
+				PrintRequestHeader:  true,
+				PrintRequestBody:    true,
+				PrintResponseHeader: true,
+				PrintResponseBody:   true,
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			options := tt.options
+			if err := parseHistoryPrintFlag(tt.historyPrintFlag, &options); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if options.History != tt.expected {
+				t.Errorf("unexpected History: expected=%+v, actual=%+v", tt.expected, options.History)
+			}
+		})
+	}
+}
+
+func TestParseHistoryPrintFlag_Error(t *testing.T) {
+	options := output.Options{}
+	if err := parseHistoryPrintFlag("hm", &options); err == nil {
+		t.Errorf("error expected for 'm' in --history-print")
+	}
+}
+
+func TestParseRedirectAuth(t *testing.T) {
+	testCases := []struct {
+		redirectAuthFlag string
+		expected         exchange.RedirectAuth
+		shouldBeError    bool
+	}{
+		{redirectAuthFlag: "", expected: exchange.RedirectAuthStrip},
+		{redirectAuthFlag: "strip", expected: exchange.RedirectAuthStrip},
+		{redirectAuthFlag: "KEEP", expected: exchange.RedirectAuthKeep},
+		{redirectAuthFlag: "forward", shouldBeError: true},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.redirectAuthFlag, func(t *testing.T) {
+			options := exchange.Options{}
+			err := parseRedirectAuth(tt.redirectAuthFlag, &options)
+			if tt.shouldBeError {
+				if err == nil {
+					t.Errorf("error expected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if options.RedirectAuth != tt.expected {
+				t.Errorf("unexpected RedirectAuth: expected=%v, actual=%v", tt.expected, options.RedirectAuth)
+			}
+		})
+	}
+}
+
+func TestParseFormatOptions(t *testing.T) {
+	testCases := []struct {
+		title             string
+		formatOptionsFlag string
+		sortedFlag        bool
+		unsortedFlag      bool
+		expected          output.FormatOptions
+		shouldBeError     bool
+	}{
+		{
+			title:    "No flags specified",
+			expected: output.DefaultFormatOptions,
+		},
+		{
+			title:             "All keys",
+			formatOptionsFlag: "json.indent:2,json.sort_keys:true,headers.sort:false",
+			expected:          output.FormatOptions{JSONIndent: 2, JSONSortKeys: true, HeadersSort: false},
+		},
+		{
+			title:      "--sorted",
+			sortedFlag: true,
+			expected:   output.FormatOptions{JSONIndent: 4, JSONSortKeys: true, HeadersSort: true},
+		},
+		{
+			title:        "--unsorted",
+			unsortedFlag: true,
+			expected:     output.FormatOptions{JSONIndent: 4, JSONSortKeys: false, HeadersSort: false},
+		},
+		{
+			title:             "--format-options overrides --sorted",
+			formatOptionsFlag: "headers.sort:false",
+			sortedFlag:        true,
+			expected:          output.FormatOptions{JSONIndent: 4, JSONSortKeys: true, HeadersSort: false},
+		},
+		{
+			title:         "Both of --sorted and --unsorted",
+			sortedFlag:    true,
+			unsortedFlag:  true,
+			shouldBeError: true,
+		},
+		{
+			title:             "Unknown key",
+			formatOptionsFlag: "xml.indent:2",
+			shouldBeError:     true,
+		},
+		{
+			title:             "Negative indent",
+			formatOptionsFlag: "json.indent:-1",
+			shouldBeError:     true,
+		},
+		{
+			title:             "Invalid boolean",
+			formatOptionsFlag: "json.sort_keys:maybe",
+			shouldBeError:     true,
+		},
+		{
+			title:             "Missing colon",
+			formatOptionsFlag: "json.indent",
+			shouldBeError:     true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			options := output.Options{}
+			err := parseFormatOptions(tt.formatOptionsFlag, tt.sortedFlag, tt.unsortedFlag, &options)
+			if tt.shouldBeError {
+				if err == nil {
+					t.Errorf("error expected but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if *options.Format != tt.expected {
+				t.Errorf("unexpected format options: expected=%+v, actual=%+v", tt.expected, *options.Format)
+			}
+		})
+	}
+}
+
+func TestDetectColorDepth(t *testing.T) {
+	testCases := []struct {
+		colorTerm  string
+		term       string
+		forceColor string
+		expected   output.ColorDepth
+	}{
+		{colorTerm: "", expected: output.ColorDepth8},
+		{colorTerm: "truecolor", expected: output.ColorDepthTrue},
+		{colorTerm: "24bit", expected: output.ColorDepthTrue},
+		{colorTerm: "rxvt-xpm", expected: output.ColorDepth8},
+		{colorTerm: "256color", expected: output.ColorDepth256},
+		{term: "xterm-256color", expected: output.ColorDepth256},
+		{term: "xterm-direct", expected: output.ColorDepthTrue},
+		{term: "xterm", expected: output.ColorDepth8},
+		{term: "xterm", forceColor: "2", expected: output.ColorDepth256},
+		{term: "xterm-256color", forceColor: "3", expected: output.ColorDepthTrue},
+		{term: "xterm-256color", forceColor: "1", expected: output.ColorDepth256},
+	}
+	for _, tt := range testCases {
+		actual := detectColorDepth(terminalInfo{colorTerm: tt.colorTerm, term: tt.term, forceColor: tt.forceColor})
+		if actual != tt.expected {
+			t.Errorf("unexpected color depth for COLORTERM=%q, TERM=%q, FORCE_COLOR=%q: expected=%v, actual=%v",
+				tt.colorTerm, tt.term, tt.forceColor, tt.expected, actual)
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	testCases := []struct {
+		title          string
+		colorFlag      string
+		prettyFlag     string
+		terminalInfo   terminalInfo
+		expectedColor  bool
+		expectedFormat bool
+	}{
+		{
+			title:          "Terminal",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: true},
+			expectedColor:  true,
+			expectedFormat: true,
+		},
+		{
+			title:          "Terminal with NO_COLOR",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: true, noColor: true},
+			expectedColor:  false,
+			expectedFormat: true,
+		},
+		{
+			title:          "Dumb terminal",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: true, term: "dumb"},
+			expectedColor:  false,
+			expectedFormat: true,
+		},
+		{
+			title:          "Piped",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: false},
+			expectedColor:  false,
+			expectedFormat: false,
+		},
+		{
+			title:          "Piped with FORCE_COLOR",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: false, forceColor: "1"},
+			expectedColor:  true,
+			expectedFormat: true,
+		},
+		{
+			title:          "Terminal with FORCE_COLOR=0",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: true, forceColor: "0"},
+			expectedColor:  false,
+			expectedFormat: true,
+		},
+		{
+			title:          "NO_COLOR takes precedence over FORCE_COLOR",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: false, noColor: true, forceColor: "1"},
+			expectedColor:  false,
+			expectedFormat: false,
+		},
+		{
+			title:          "--color=always",
+			colorFlag:      "always",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: false, noColor: true},
+			expectedColor:  true,
+			expectedFormat: true,
+		},
+		{
+			title:          "--color=never",
+			colorFlag:      "never",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: true, forceColor: "1"},
+			expectedColor:  false,
+			expectedFormat: true,
+		},
+		{
+			title:          "--pretty=format with FORCE_COLOR",
+			prettyFlag:     "format",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: false, forceColor: "1"},
+			expectedColor:  false,
+			expectedFormat: true,
+		},
+		{
+			title:          "--pretty=format with --color=always",
+			colorFlag:      "always",
+			prettyFlag:     "format",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: false},
+			expectedColor:  true,
+			expectedFormat: true,
+		},
+		{
+			title:          "--pretty=all with NO_COLOR",
+			prettyFlag:     "all",
+			terminalInfo:   terminalInfo{stdoutIsTerminal: false, noColor: true},
+			expectedColor:  true,
+			expectedFormat: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			var options output.Options
+			if err := parsePretty(tt.prettyFlag, tt.terminalInfo.stdoutIsTerminal, &options); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if err := parseColor(tt.colorFlag, tt.prettyFlag, tt.terminalInfo, &options); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if options.EnableColor != tt.expectedColor || options.EnableFormat != tt.expectedFormat {
+				t.Errorf("unexpected options: expected=(color=%v, format=%v), actual=(color=%v, format=%v)",
+					tt.expectedColor, tt.expectedFormat, options.EnableColor, options.EnableFormat)
+			}
+		})
+	}
+}
+
+func TestParseColor_Error(t *testing.T) {
+	testCases := []struct {
+		colorFlag  string
+		prettyFlag string
+	}{
+		{colorFlag: "sometimes"},
+		{colorFlag: "always", prettyFlag: "none"},
+	}
+	for _, tt := range testCases {
+		var options output.Options
+		if err := parseColor(tt.colorFlag, tt.prettyFlag, terminalInfo{}, &options); err == nil {
+			t.Errorf("error expected for --color=%s --pretty=%s", tt.colorFlag, tt.prettyFlag)
+		}
+	}
+}
+
+func TestDetectHyperlinks(t *testing.T) {
+	testCases := []struct {
+		env      map[string]string
+		expected bool
+	}{
+		{env: map[string]string{}, expected: false},
+		{env: map[string]string{"TERM_PROGRAM": "iTerm.app"}, expected: true},
+		{env: map[string]string{"TERM_PROGRAM": "Apple_Terminal"}, expected: false},
+		{env: map[string]string{"VTE_VERSION": "6003"}, expected: true},
+		{env: map[string]string{"VTE_VERSION": "4205"}, expected: false},
+		{env: map[string]string{"WT_SESSION": "abc"}, expected: true},
+		{env: map[string]string{"FORCE_HYPERLINK": "1"}, expected: true},
+		{env: map[string]string{"FORCE_HYPERLINK": "0", "TERM_PROGRAM": "iTerm.app"}, expected: false},
+	}
+	for _, tt := range testCases {
+		actual := detectHyperlinks(func(key string) string { return tt.env[key] })
+		if actual != tt.expected {
+			t.Errorf("unexpected result for %v: expected=%v, actual=%v", tt.env, tt.expected, actual)
+		}
+	}
+}
+
+func TestParseView(t *testing.T) {
+	testCases := []struct {
+		title           string
+		viewFlag        string
+		columnsFlag     string
+		expectedView    output.View
+		expectedColumns []string
+		shouldBeError   bool
+	}{
+		{title: "No flags specified", expectedView: output.JSONView},
+		{title: "--view=json", viewFlag: "json", expectedView: output.JSONView},
+		{title: "--view=table", viewFlag: "table", expectedView: output.TableView},
+		{title: "--columns", columnsFlag: "id, name", expectedView: output.TableView, expectedColumns: []string{"id", "name"}},
+		{title: "Unknown view", viewFlag: "yaml", shouldBeError: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			options := output.Options{}
+			err := parseView(tt.viewFlag, tt.columnsFlag, &options)
+			if tt.shouldBeError {
+				if err == nil {
+					t.Errorf("error expected but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if options.View != tt.expectedView {
+				t.Errorf("unexpected view: expected=%v, actual=%v", tt.expectedView, options.View)
+			}
+			if !reflect.DeepEqual(options.Columns, tt.expectedColumns) {
+				t.Errorf("unexpected columns: expected=%v, actual=%v", tt.expectedColumns, options.Columns)
+			}
 		})
 	}
 }
@@ -3,10 +3,14 @@ package httpie
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"os"
+	"time"
 
 	"github.com/nojima/httpie-go/exchange"
 	"github.com/nojima/httpie-go/flags"
@@ -55,6 +59,18 @@ func Main(options *Options) error {
 	return nil
 }
 
+// exitStatusTooManyRedirects is the exit status when --max-redirects is exceeded.
+const exitStatusTooManyRedirects = 6
+
+// ExitStatus returns the exit status for err returned by Main. Errors caused
+// by exceeding --max-redirects are distinguished from others as httpie does.
+func ExitStatus(err error) int {
+	if exchange.IsTooManyRedirects(err) {
+		return exitStatusTooManyRedirects
+	}
+	return 1
+}
+
 func getExitStatus(statusCode int) int {
 	if 300 <= statusCode && statusCode < 600 {
 		return statusCode / 100
@@ -63,9 +79,25 @@ func getExitStatus(statusCode int) int {
 }
 
 func Exchange(in *input.Input, exchangeOptions *exchange.Options, outputOptions *output.Options) (int, error) {
+	return exchangeTo(os.Stdout, os.Stderr, in, exchangeOptions, outputOptions)
+}
+
+// exchangeTo is the same as Exchange except that it prints to out and errOut.
+func exchangeTo(out io.Writer, errOut io.Writer, in *input.Input, exchangeOptions *exchange.Options, outputOptions *output.Options) (status int, err error) {
 	// Prepare printer
-	writer := bufio.NewWriter(os.Stdout)
-	defer writer.Flush()
+	pager := output.NewPagerWriter(out, outputOptions)
+	writer := bufio.NewWriter(pager)
+	var problemSummary string
+	defer func() {
+		writer.Flush()
+		if closeErr := pager.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		// The pager holds the response body until it is closed
+		if problemSummary != "" {
+			fmt.Fprintf(errOut, "ERROR: %s\n", problemSummary)
+		}
+	}()
 	printer := output.NewPrinter(writer, outputOptions)
 
 	// Build HTTP request
@@ -74,42 +106,55 @@ func Exchange(in *input.Input, exchangeOptions *exchange.Options, outputOptions
 		return -1, err
 	}
 
-	// Print HTTP request
-	if outputOptions.PrintRequestHeader || outputOptions.PrintRequestBody {
-		// `request` does not contain HTTP headers that HttpClient.Do adds.
-		// We can get these headers by DumpRequestOut and ReadRequest.
-		dump, err := httputil.DumpRequestOut(request, true)
+	// Ask for the rest of a partial file with --continue
+	var file *output.FileWriter
+	if outputOptions.Download {
+		file = output.NewFileWriter(in.URL, outputOptions)
+		if err := file.AddResumeHeaders(request.Header); err != nil {
+			return -1, err
+		}
+	}
+
+	// Print HTTP request. With --all, requests are printed when their responses
+	// arrive since whether they are intermediate is not known until then.
+	printAll := outputOptions.PrintAll && exchangeOptions.FollowRedirects
+	var pendingRequest *http.Request
+	if printAll {
+		r, err := dumpRequest(request)
 		if err != nil {
-			return -1, err // should not happen
+			return -1, err
 		}
-		r, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(dump)))
+		pendingRequest = r
+	} else if outputOptions.PrintRequestHeader || outputOptions.PrintRequestBody {
+		r, err := dumpRequest(request)
 		if err != nil {
-			return -1, err // should not happen
+			return -1, err
 		}
-		defer r.Body.Close()
-
-		// ReadRequest deletes Host header. We must restore it.
-		if request.Host != "" {
-			r.Header.Set("Host", request.Host)
-		} else {
-			r.Header.Set("Host", request.URL.Host)
+		if err := printDumpedRequest(printer, writer, r, outputOptions.PrintRequestHeader, outputOptions.PrintRequestBody, outputOptions); err != nil {
+			return -1, err
 		}
-
-		if outputOptions.PrintRequestHeader {
-			if err := printer.PrintRequestLine(r); err != nil {
-				return -1, err
+		writer.Flush()
+	}
+	if printAll {
+		options := *exchangeOptions
+		options.OnRedirect = func(response *http.Response, next *http.Request) error {
+			history := &outputOptions.History
+			if pendingRequest != nil {
+				if err := printDumpedRequest(printer, writer, pendingRequest, history.PrintRequestHeader, history.PrintRequestBody, outputOptions); err != nil {
+					return err
+				}
 			}
-			if err := printer.PrintHeader(r.Header); err != nil {
-				return -1, err
+			if err := printIntermediateResponse(printer, writer, response, history.PrintResponseHeader, history.PrintResponseBody, outputOptions); err != nil {
+				return err
 			}
-		}
-		if outputOptions.PrintRequestBody {
-			if err := printer.PrintBody(r.Body, r.Header.Get("Content-Type")); err != nil {
-				return -1, err
+			r, err := dumpRequest(next)
+			if err != nil {
+				return err
 			}
+			pendingRequest = r
+			return writer.Flush()
 		}
-		fmt.Fprintln(writer)
-		writer.Flush()
+		exchangeOptions = &options
 	}
 
 	// Send HTTP request and receive HTTP request
@@ -117,12 +162,28 @@ func Exchange(in *input.Input, exchangeOptions *exchange.Options, outputOptions
 	if err != nil {
 		return -1, err
 	}
+	var tracer *exchange.Tracer
+	if outputOptions.PrintMeta || outputOptions.PrintTiming {
+		request, tracer = exchange.WithTrace(request)
+	}
+	start := time.Now()
 	resp, err := httpClient.Do(request)
+	// The request that exceeds --max-redirects is not sent
+	if pendingRequest != nil && !exchange.IsTooManyRedirects(err) {
+		// The last request is printed as the final exchange even if it fails
+		if err := printDumpedRequest(printer, writer, pendingRequest, outputOptions.PrintRequestHeader, outputOptions.PrintRequestBody, outputOptions); err != nil {
+			return -1, err
+		}
+		writer.Flush()
+	}
 	if err != nil {
 		return -1, errors.Wrap(err, "sending HTTP request")
 	}
 	defer resp.Body.Close()
+	received := &countingReadCloser{ReadCloser: resp.Body}
+	resp.Body = received
 
+	var responseBody io.Reader = resp.Body
 	if outputOptions.PrintResponseHeader {
 		if err := printer.PrintStatusLine(resp.Proto, resp.Status, resp.StatusCode); err != nil {
 			return -1, err
@@ -130,23 +191,118 @@ func Exchange(in *input.Input, exchangeOptions *exchange.Options, outputOptions
 		if err := printer.PrintHeader(resp.Header); err != nil {
 			return -1, err
 		}
+		if outputOptions.DecodeJWT {
+			if outputOptions.Download {
+				// Downloaded files are not examined
+				responseBody = nil
+			}
+			responseBody, err = output.PrintJWTs(printer, resp.Header, responseBody, resp.Header.Get("Content-Type"))
+			if err != nil {
+				return -1, err
+			}
+		}
 		writer.Flush()
 	}
 
-	if outputOptions.Download {
-		file := output.NewFileWriter(in.URL, outputOptions)
-
-		if err := printer.PrintDownload(resp.ContentLength, file.Filename()); err != nil {
+	if outputOptions.PrintTLS {
+		if err := output.PrintTLS(printer, resp.TLS); err != nil {
 			return -1, err
 		}
+		fmt.Fprintln(writer)
 		writer.Flush()
+	}
 
-		if err = file.Download(resp); err != nil {
+	if outputOptions.Download {
+		complete, err := file.IsComplete(resp)
+		if err != nil {
 			return -1, err
 		}
+		if complete {
+			if err := output.PrintDownloadComplete(printer, file.Filename()); err != nil {
+				return -1, err
+			}
+		} else {
+			if file.Resumes(resp) {
+				err = output.PrintResume(printer, file.Offset(), resp.ContentLength, file.Filename())
+			} else {
+				err = printer.PrintDownload(resp.ContentLength, file.Filename())
+			}
+			if err != nil {
+				return -1, err
+			}
+			writer.Flush()
+
+			if err = file.Download(resp); err != nil {
+				return -1, err
+			}
+		}
 	} else {
+		body := responseBody
+		contentType := resp.Header.Get("Content-Type")
+		if outputOptions.ResponseCharset != "" {
+			contentType = output.WithCharset(contentType, outputOptions.ResponseCharset)
+		}
+
+		// Keep the body of an error response to summarize Problem Details after printing it
+		var errorBody []byte
+		errorContentType := contentType
+		if outputOptions.ProblemSummary && exchangeOptions.CheckStatus && getExitStatus(resp.StatusCode) != 0 {
+			errorBody, err = ioutil.ReadAll(body)
+			if err != nil {
+				return -1, errors.Wrap(err, "reading response body")
+			}
+			body = bytes.NewReader(errorBody)
+		}
+
 		if outputOptions.PrintResponseBody {
-			if err := printer.PrintBody(resp.Body, resp.Header.Get("Content-Type")); err != nil {
+			if outputOptions.Filter != nil {
+				body, err = outputOptions.Filter.Apply(body, contentType)
+				if err != nil {
+					return -1, err
+				}
+				// Selected values are always JSON
+				contentType = "application/json"
+			}
+			if err := printer.PrintBody(body, contentType); err != nil {
+				return -1, err
+			}
+		}
+
+		if summary, ok := output.ProblemSummary(errorBody, errorContentType); ok {
+			problemSummary = summary
+		}
+	}
+
+	if outputOptions.PrintMeta || outputOptions.PrintTiming {
+		// Read the rest of the body to measure the whole exchange
+		if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+			return -1, errors.Wrap(err, "reading response body")
+		}
+		end := received.eof
+		trace := tracer.Trace()
+		if outputOptions.PrintResponseBody && !outputOptions.Download {
+			fmt.Fprintln(writer)
+		}
+		if outputOptions.PrintMeta {
+			meta := &output.Metadata{
+				Elapsed:      end.Sub(start),
+				BodySize:     received.count,
+				Decompressed: resp.Uncompressed,
+				Proto:        resp.Proto,
+				RemoteAddr:   trace.RemoteAddr,
+			}
+			if resp.TLS != nil {
+				meta.TLSVersion = resp.TLS.Version
+			}
+			if err := output.PrintMetadata(printer, meta); err != nil {
+				return -1, err
+			}
+		}
+		if outputOptions.PrintTiming {
+			if outputOptions.PrintMeta {
+				fmt.Fprintln(writer)
+			}
+			if err := output.PrintTiming(printer, buildTiming(&trace, start, end)); err != nil {
 				return -1, err
 			}
 		}
@@ -154,3 +310,121 @@ func Exchange(in *input.Input, exchangeOptions *exchange.Options, outputOptions
 
 	return resp.StatusCode, nil
 }
+
+// buildTiming converts trace into the phases of the exchange from start to end.
+func buildTiming(trace *exchange.Trace, start time.Time, end time.Time) *output.Timing {
+	timing := &output.Timing{Total: end.Sub(start), Reused: trace.Reused}
+	addPhase := func(key string, from time.Time, to time.Time) {
+		if from.IsZero() || to.IsZero() {
+			return
+		}
+		timing.Phases = append(timing.Phases, output.TimingPhase{
+			Key:      key,
+			Start:    from.Sub(start),
+			Duration: to.Sub(from),
+		})
+	}
+	addPhase(output.TimingDNS, trace.DNSStart, trace.DNSDone)
+	addPhase(output.TimingConnect, trace.ConnectStart, trace.ConnectDone)
+	addPhase(output.TimingTLS, trace.TLSStart, trace.TLSDone)
+	addPhase(output.TimingTTFB, trace.WroteRequest, trace.FirstByte)
+	addPhase(output.TimingTransfer, trace.FirstByte, end)
+	return timing
+}
+
+// dumpRequest returns a copy of request as it is sent.
+func dumpRequest(request *http.Request) (*http.Request, error) {
+	// `request` does not contain HTTP headers that HttpClient.Do adds.
+	// We can get these headers by DumpRequestOut and ReadRequest.
+	// The request is dumped without its context so that a trace in it is not triggered.
+	dumped := request.WithContext(context.Background())
+	dump, err := httputil.DumpRequestOut(dumped, true)
+	if err != nil {
+		return nil, err // should not happen
+	}
+	// DumpRequestOut has replaced the body with a copy
+	request.Body = dumped.Body
+	r, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(dump)))
+	if err != nil {
+		return nil, err // should not happen
+	}
+
+	// ReadRequest deletes Host header. We must restore it.
+	if request.Host != "" {
+		r.Header.Set("Host", request.Host)
+	} else {
+		r.Header.Set("Host", request.URL.Host)
+	}
+	return r, nil
+}
+
+// printDumpedRequest prints the header and the body of r returned by dumpRequest.
+func printDumpedRequest(printer output.Printer, writer io.Writer, r *http.Request, printHeader bool, printBody bool, outputOptions *output.Options) error {
+	if !printHeader && !printBody {
+		return nil
+	}
+	if printHeader {
+		if err := printer.PrintRequestLine(r); err != nil {
+			return err
+		}
+		if err := printer.PrintHeader(r.Header); err != nil {
+			return err
+		}
+	}
+	var requestBody io.Reader = r.Body
+	if printHeader && outputOptions.DecodeJWT {
+		var err error
+		requestBody, err = output.PrintJWTs(printer, r.Header, requestBody, r.Header.Get("Content-Type"))
+		if err != nil {
+			return err
+		}
+	}
+	if printBody {
+		if err := output.PrintRequestBody(printer, requestBody, r.Header.Get("Content-Type")); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(writer)
+	return err
+}
+
+// printIntermediateResponse prints a redirect response followed with --all.
+func printIntermediateResponse(printer output.Printer, writer io.Writer, resp *http.Response, printHeader bool, printBody bool, outputOptions *output.Options) error {
+	if printHeader {
+		if err := printer.PrintStatusLine(resp.Proto, resp.Status, resp.StatusCode); err != nil {
+			return err
+		}
+		if err := printer.PrintHeader(resp.Header); err != nil {
+			return err
+		}
+	}
+	if printBody {
+		contentType := resp.Header.Get("Content-Type")
+		if outputOptions.ResponseCharset != "" {
+			contentType = output.WithCharset(contentType, outputOptions.ResponseCharset)
+		}
+		if err := printer.PrintBody(resp.Body, contentType); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(writer)
+		return err
+	}
+	return nil
+}
+
+// countingReadCloser counts the bytes read from the underlying reader, and
+// records when it reaches EOF.
+type countingReadCloser struct {
+	io.ReadCloser
+	count int64
+	eof   time.Time
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.count += int64(n)
+	if err == io.EOF && r.eof.IsZero() {
+		r.eof = time.Now()
+	}
+	return n, err
+}
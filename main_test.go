@@ -0,0 +1,171 @@
+package httpie
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/nojima/httpie-go/exchange"
+	"github.com/nojima/httpie-go/input"
+	"github.com/nojima/httpie-go/output"
+)
+
+func newInput(t *testing.T, rawURL string) *input.Input {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	return &input.Input{Method: "GET", URL: u}
+}
+
+func TestExchange_Meta_Gzip(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(strings.Repeat("a", 2048)))
+		gz.Close()
+	}))
+	defer server.Close()
+	outputOptions := &output.Options{PrintMeta: true}
+	var out bytes.Buffer
+
+	// Exercise
+	_, err := exchangeTo(&out, ioutil.Discard, newInput(t, server.URL), &exchange.Options{}, outputOptions)
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Verify
+	expected := "2K (2048 bytes), decompressed from gzip"
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("body size should be the decompressed size: expected=%q, actual=%q", expected, out.String())
+	}
+}
+
+func TestExchange_PrintAll(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/r1":
+			http.Redirect(w, r, "/r2", http.StatusFound)
+		case "/r2":
+			http.Redirect(w, r, "/final", http.StatusTemporaryRedirect)
+		default:
+			w.Write([]byte("final"))
+		}
+	}))
+	defer server.Close()
+	one := 1
+	testCases := []struct {
+		title         string
+		maxRedirects  *int
+		history       output.HistoryOptions
+		expected      []string
+		shouldBeError bool
+	}{
+		{
+			title:   "Intermediate requests are printed as specified by History",
+			history: output.HistoryOptions{PrintResponseHeader: true},
+			expected: []string{
+				"HTTP/1.1 302 Found",
+				"HTTP/1.1 307 Temporary Redirect",
+				"GET /final HTTP/1.1",
+				"HTTP/1.1 200 OK",
+			},
+		},
+		{
+			title:   "Initial request is printed as an intermediate one",
+			history: output.HistoryOptions{PrintRequestHeader: true},
+			expected: []string{
+				"GET /r1 HTTP/1.1",
+				"GET /r2 HTTP/1.1",
+				"GET /final HTTP/1.1",
+				"HTTP/1.1 200 OK",
+			},
+		},
+		{
+			title:        "Request exceeding max redirects is not printed",
+			maxRedirects: &one,
+			history:      output.HistoryOptions{PrintRequestHeader: true, PrintResponseHeader: true},
+			expected: []string{
+				"GET /r1 HTTP/1.1",
+				"HTTP/1.1 302 Found",
+				"GET /r2 HTTP/1.1",
+				"HTTP/1.1 307 Temporary Redirect",
+			},
+			shouldBeError: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			exchangeOptions := &exchange.Options{
+				FollowRedirects: true,
+				MaxRedirects:    tt.maxRedirects,
+			}
+			outputOptions := &output.Options{
+				PrintRequestHeader:  true,
+				PrintResponseHeader: true,
+				PrintAll:            true,
+				History:             tt.history,
+			}
+			var out bytes.Buffer
+
+			// Exercise
+			_, err := exchangeTo(&out, ioutil.Discard, newInput(t, server.URL+"/r1"), exchangeOptions, outputOptions)
+			if (err != nil) != tt.shouldBeError {
+				t.Fatalf("unexpected error: shouldBeError=%v, err=%+v", tt.shouldBeError, err)
+			}
+			if tt.shouldBeError && !exchange.IsTooManyRedirects(err) {
+				t.Errorf("error should be caused by too many redirects: err=%+v", err)
+			}
+
+			// Verify
+			var actual []string
+			for _, line := range strings.Split(out.String(), "\n") {
+				if strings.HasPrefix(line, "GET ") || strings.HasPrefix(line, "HTTP/") {
+					actual = append(actual, line)
+				}
+			}
+			if strings.Join(actual, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("unexpected exchanges: expected=%q, actual=%q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestExchange_ProblemSummary_Pager(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"title":"Out of credit"}`))
+	}))
+	defer server.Close()
+	exchangeOptions := &exchange.Options{CheckStatus: true}
+	outputOptions := &output.Options{
+		PrintResponseBody: true,
+		ProblemSummary:    true,
+		Pager:             "cat",
+		PagerAlways:       true,
+	}
+	// stdout and stderr are usually the same terminal
+	var out bytes.Buffer
+
+	// Exercise
+	_, err := exchangeTo(&out, &out, newInput(t, server.URL), exchangeOptions, outputOptions)
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Verify
+	expected := "{\"title\":\"Out of credit\"}ERROR: Out of credit\n"
+	if out.String() != expected {
+		t.Errorf("summary should be printed after the body: expected=%q, actual=%q", expected, out.String())
+	}
+}
@@ -0,0 +1,256 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/mattn/go-runewidth"
+)
+
+// headerAnnotation explains a header field. inline is printed after the value
+// and rows are printed beneath the field as a two-column table.
+type headerAnnotation struct {
+	inline string
+	rows   [][2]string
+}
+
+// annotateHeader explains well-known header fields. It returns false if there
+// is nothing to explain.
+func annotateHeader(name string, value string, now time.Time) (headerAnnotation, bool) {
+	var a headerAnnotation
+	switch name {
+	case "Date", "Last-Modified", "Expires":
+		t, err := http.ParseTime(value)
+		if err != nil {
+			return a, false
+		}
+		a.inline = relativeTime(t, now)
+	case "Content-Length":
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || n < 1024 {
+			return a, false
+		}
+		a.inline = bytefmt.ByteSize(n)
+	case "Cache-Control":
+		a.rows = annotateCacheControl(value)
+	case "Set-Cookie":
+		a.rows = annotateSetCookie(value, now)
+	case "Link":
+		a.rows = annotateLink(value)
+	}
+	return a, a.inline != "" || len(a.rows) > 0
+}
+
+// relativeTime returns a phrase like "3 minutes ago" or "in 2 days".
+func relativeTime(t time.Time, now time.Time) string {
+	d := t.Sub(now)
+	switch {
+	case d <= -time.Second:
+		return humanizeDuration(-d) + " ago"
+	case d >= time.Second:
+		return "in " + humanizeDuration(d)
+	default:
+		return "now"
+	}
+}
+
+// humanizeDuration returns d in the largest unit that fits, rounded down.
+func humanizeDuration(d time.Duration) string {
+	units := []struct {
+		noun   string
+		length time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for _, unit := range units {
+		if d >= unit.length {
+			return pluralize(int(d/unit.length), unit.noun)
+		}
+	}
+	return pluralize(int(d/time.Second), "second")
+}
+
+// humanizeSeconds returns a phrase like "1 hour" for a header value in seconds.
+func humanizeSeconds(value string) (string, bool) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return "", false
+	}
+	return humanizeDuration(time.Duration(seconds) * time.Second), true
+}
+
+var cacheControlDescriptions = map[string]string{
+	"public":           "may be stored by any cache",
+	"private":          "may be stored only by the browser",
+	"no-cache":         "must be revalidated before each use",
+	"no-store":         "must not be stored",
+	"no-transform":     "must not be transformed by intermediaries",
+	"must-revalidate":  "must be revalidated once stale",
+	"proxy-revalidate": "must be revalidated by shared caches once stale",
+	"immutable":        "will not change while fresh",
+	"only-if-cached":   "only a cached response is wanted",
+	"max-stale":        "a stale response is acceptable",
+}
+
+// cacheControlDurations describes directives whose argument is in seconds.
+var cacheControlDurations = map[string]string{
+	"max-age":                "fresh for %s",
+	"s-maxage":               "fresh in shared caches for %s",
+	"stale-while-revalidate": "may be used stale for %s while revalidating",
+	"stale-if-error":         "may be used stale for %s if an error occurs",
+	"max-stale":              "a response stale for up to %s is acceptable",
+	"min-fresh":              "a response fresh for at least %s is wanted",
+}
+
+func annotateCacheControl(value string) [][2]string {
+	var rows [][2]string
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+		name := strings.ToLower(directive)
+		argument := ""
+		if i := strings.Index(directive, "="); i >= 0 {
+			name = strings.ToLower(strings.TrimSpace(directive[:i]))
+			argument = strings.Trim(strings.TrimSpace(directive[i+1:]), `"`)
+		}
+
+		description := cacheControlDescriptions[name]
+		if format, ok := cacheControlDurations[name]; ok && argument != "" {
+			if duration, ok := humanizeSeconds(argument); ok {
+				description = fmt.Sprintf(format, duration)
+			}
+		}
+		rows = append(rows, [2]string{directive, description})
+	}
+	return rows
+}
+
+// annotateSetCookie splits a Set-Cookie value into the cookie and its attributes.
+func annotateSetCookie(value string, now time.Time) [][2]string {
+	var rows [][2]string
+	for i, pair := range strings.Split(value, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, attribute := pair, ""
+		if j := strings.Index(pair, "="); j >= 0 {
+			name, attribute = strings.TrimSpace(pair[:j]), strings.TrimSpace(pair[j+1:])
+		}
+		if i == 0 {
+			rows = append(rows, [2]string{"name", name}, [2]string{"value", attribute})
+			continue
+		}
+
+		switch strings.ToLower(name) {
+		case "expires":
+			if t, err := http.ParseTime(attribute); err == nil {
+				attribute += " (" + relativeTime(t, now) + ")"
+			}
+		case "max-age":
+			if duration, ok := humanizeSeconds(attribute); ok {
+				attribute += " (" + duration + ")"
+			}
+		}
+		if attribute == "" {
+			attribute = "yes"
+		}
+		rows = append(rows, [2]string{name, attribute})
+	}
+	return rows
+}
+
+// annotateLink splits a Link value (RFC 8288) into pairs of rel and URL.
+func annotateLink(value string) [][2]string {
+	var rows [][2]string
+	for {
+		start := strings.Index(value, "<")
+		if start < 0 {
+			return rows
+		}
+		end := strings.Index(value[start:], ">")
+		if end < 0 {
+			return rows
+		}
+		url := value[start+1 : start+end]
+		value = value[start+end+1:]
+
+		// Parameters continue until a comma outside quotes
+		params, rest := value, ""
+		quoted := false
+		for i, c := range value {
+			if c == '"' {
+				quoted = !quoted
+			}
+			if c == ',' && !quoted {
+				params, rest = value[:i], value[i+1:]
+				break
+			}
+		}
+		value = rest
+
+		rel := ""
+		for _, param := range strings.Split(params, ";") {
+			param = strings.TrimSpace(param)
+			if i := strings.Index(param, "="); i >= 0 && strings.EqualFold(strings.TrimSpace(param[:i]), "rel") {
+				rel = strings.Trim(strings.TrimSpace(param[i+1:]), `"`)
+			}
+		}
+		if rel == "" {
+			rel = "(no rel)"
+		}
+		rows = append(rows, [2]string{rel, url})
+	}
+}
+
+// writeAnnotatedField writes a header field followed by its annotation.
+// formattedValue is value to be written, which may be colorized and wrapped.
+func writeAnnotatedField(
+	w io.Writer,
+	name string,
+	value string,
+	formattedValue interface{},
+	now time.Time,
+	palette *HeaderPalette,
+	colorize func(arg interface{}, color Color) interface{},
+) {
+	annotation, ok := annotateHeader(name, value, now)
+	fmt.Fprintf(w, "%s%s %s",
+		colorize(name, palette.FieldName),
+		colorize(":", palette.FieldSeparator),
+		formattedValue)
+	if ok && annotation.inline != "" {
+		fmt.Fprintf(w, " %s", colorize("("+annotation.inline+")", palette.FieldSeparator))
+	}
+	fmt.Fprintln(w)
+	if !ok {
+		return
+	}
+
+	width := 0
+	for _, row := range annotation.rows {
+		if w := runewidth.StringWidth(row[0]); w > width {
+			width = w
+		}
+	}
+	for _, row := range annotation.rows {
+		if row[1] == "" {
+			fmt.Fprintf(w, "    %s\n", colorize(row[0], palette.FieldName))
+			continue
+		}
+		fmt.Fprintf(w, "    %s%s  %s\n",
+			colorize(row[0], palette.FieldName),
+			strings.Repeat(" ", width-runewidth.StringWidth(row[0])),
+			colorize(row[1], palette.FieldValue))
+	}
+}
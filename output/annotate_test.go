@@ -0,0 +1,130 @@
+package output
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnnotateHeader(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		value    string
+		expected headerAnnotation
+		ok       bool
+	}{
+		{
+			name:     "Date",
+			value:    "Thu, 02 Jan 2020 03:01:05 GMT",
+			expected: headerAnnotation{inline: "3 minutes ago"},
+			ok:       true,
+		},
+		{
+			name:     "Expires",
+			value:    "Sat, 04 Jan 2020 04:00:00 GMT",
+			expected: headerAnnotation{inline: "in 2 days"},
+			ok:       true,
+		},
+		{
+			name:     "Last-Modified",
+			value:    "Thu, 02 Jan 2020 03:04:05 GMT",
+			expected: headerAnnotation{inline: "now"},
+			ok:       true,
+		},
+		{
+			name:  "Expires",
+			value: "0",
+			ok:    false,
+		},
+		{
+			name:     "Content-Length",
+			value:    "1572864",
+			expected: headerAnnotation{inline: "1.5M"},
+			ok:       true,
+		},
+		{
+			name:  "Content-Length",
+			value: "512",
+			ok:    false,
+		},
+		{
+			name:  "Cache-Control",
+			value: "public, max-age=3600, stale-while-revalidate=60, x-custom",
+			expected: headerAnnotation{rows: [][2]string{
+				{"public", "may be stored by any cache"},
+				{"max-age=3600", "fresh for 1 hour"},
+				{"stale-while-revalidate=60", "may be used stale for 1 minute while revalidating"},
+				{"x-custom", ""},
+			}},
+			ok: true,
+		},
+		{
+			name:  "Set-Cookie",
+			value: "sid=abc; Path=/; Expires=Thu, 02 Jan 2020 05:04:05 GMT; Max-Age=7200; HttpOnly",
+			expected: headerAnnotation{rows: [][2]string{
+				{"name", "sid"},
+				{"value", "abc"},
+				{"Path", "/"},
+				{"Expires", "Thu, 02 Jan 2020 05:04:05 GMT (in 2 hours)"},
+				{"Max-Age", "7200 (2 hours)"},
+				{"HttpOnly", "yes"},
+			}},
+			ok: true,
+		},
+		{
+			name:  "Link",
+			value: `<https://example.com/items?page=2&a=b,c>; rel="next", <https://example.com/items?page=5>; rel=last; title="a, b", </style.css>`,
+			expected: headerAnnotation{rows: [][2]string{
+				{"next", "https://example.com/items?page=2&a=b,c"},
+				{"last", "https://example.com/items?page=5"},
+				{"(no rel)", "/style.css"},
+			}},
+			ok: true,
+		},
+		{
+			name:  "Content-Type",
+			value: "application/json",
+			ok:    false,
+		},
+	}
+	for _, tt := range testCases {
+		actual, ok := annotateHeader(tt.name, tt.value, now)
+		if ok != tt.ok || (ok && !reflect.DeepEqual(actual, tt.expected)) {
+			t.Errorf("unexpected annotation of %s: %s: expected=%+v, actual=%+v", tt.name, tt.value, tt.expected, actual)
+		}
+	}
+}
+
+func TestPlainPrinter_PrintHeader_Annotate(t *testing.T) {
+	// Setup
+	var buffer strings.Builder
+	printer := NewPlainPrinterWithConfig(PlainPrinterConfig{Writer: &buffer, Annotate: true})
+	header := http.Header{
+		"Cache-Control":  {"no-store"},
+		"Content-Length": {"2048"},
+		"Link":           {`<https://example.com/?page=2>; rel="next", <https://example.com/?page=10>; rel="last"`},
+	}
+
+	// Exercise
+	if err := printer.PrintHeader(header); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Verify
+	expected := strings.Join([]string{
+		"Cache-Control: no-store",
+		"    no-store  must not be stored",
+		"Content-Length: 2048 (2K)",
+		`Link: <https://example.com/?page=2>; rel="next", <https://example.com/?page=10>; rel="last"`,
+		"    next  https://example.com/?page=2",
+		"    last  https://example.com/?page=10",
+		"",
+		"",
+	}, "\n")
+	if buffer.String() != expected {
+		t.Errorf("unexpected output: expected=\n%s\nactual=\n%s", expected, buffer.String())
+	}
+}
@@ -0,0 +1,164 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// BinaryMode is how to print bodies that look binary.
+type BinaryMode int
+
+const (
+	// BinaryRaw writes binary bodies as they are.
+	BinaryRaw BinaryMode = iota
+	// BinaryNotice prints a notice instead of binary bodies.
+	BinaryNotice
+	// BinaryHexdump prints binary bodies in the format of `xxd`.
+	BinaryHexdump
+)
+
+// binarySniffLength is the size of the first chunk inspected by sniffBinary.
+const binarySniffLength = 1024
+
+const binaryNotice = `+-----------------------------------------+
+| NOTE: binary data not shown in terminal |
++-----------------------------------------+
+`
+
+// sniffBinary reads the first chunk of body and reports whether body looks
+// binary, i.e. the chunk contains NUL bytes or is not valid UTF-8. The chunk
+// is examined after being converted from the charset of contentType (see
+// decodeBody) so that text in other charsets is not regarded as binary.
+// The returned reader yields the whole body including the first chunk as it is.
+func sniffBinary(body io.Reader, contentType string) (io.Reader, bool, error) {
+	chunk, body, err := peekBody(body, binarySniffLength)
+	if err != nil {
+		return nil, false, err
+	}
+	decoded, err := decodeBody(bytes.NewReader(chunk), contentType)
+	if err != nil {
+		return nil, false, err
+	}
+	text, err := ioutil.ReadAll(decoded)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "decoding body")
+	}
+	return body, isBinary(text, len(chunk) == binarySniffLength), nil
+}
+
+func isBinary(chunk []byte, truncated bool) bool {
+	if bytes.IndexByte(chunk, 0) != -1 {
+		return true
+	}
+	if truncated {
+		// The last character may be cut in the middle
+		for i := 0; i < utf8.UTFMax-1 && len(chunk) > 0; i++ {
+			if utf8.RuneStart(chunk[len(chunk)-1-i]) {
+				if !utf8.FullRune(chunk[len(chunk)-1-i:]) {
+					chunk = chunk[:len(chunk)-1-i]
+				}
+				break
+			}
+		}
+	}
+	return !utf8.Valid(chunk)
+}
+
+// hexdumpByteKind classifies bytes to colorize hexdumps.
+type hexdumpByteKind int
+
+const (
+	hexdumpOffset hexdumpByteKind = iota
+	hexdumpPrintable
+	hexdumpWhitespace
+	hexdumpNUL
+	hexdumpOther
+)
+
+func kindOfByte(b byte) hexdumpByteKind {
+	switch {
+	case b == 0:
+		return hexdumpNUL
+	case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+		return hexdumpWhitespace
+	case 0x20 < b && b < 0x7f:
+		return hexdumpPrintable
+	default:
+		return hexdumpOther
+	}
+}
+
+const hexdumpBytesPerLine = 16
+
+// writeHexdump writes body to w in the format of `xxd`. colorize is called
+// for each offset, byte and character of the dump.
+func writeHexdump(w io.Writer, body io.Reader, colorize func(arg interface{}, kind hexdumpByteKind) interface{}) error {
+	line := make([]byte, hexdumpBytesPerLine)
+	for offset := 0; ; offset += hexdumpBytesPerLine {
+		n, err := io.ReadFull(body, line)
+		if n > 0 {
+			writeHexdumpLine(w, offset, line[:n], colorize)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "reading body")
+		}
+	}
+}
+
+func writeHexdumpLine(w io.Writer, offset int, line []byte, colorize func(arg interface{}, kind hexdumpByteKind) interface{}) {
+	fmt.Fprintf(w, "%s:", colorize(fmt.Sprintf("%08x", offset), hexdumpOffset))
+
+	// Hex part: 8 groups of 2 bytes
+	for i := 0; i < hexdumpBytesPerLine; i++ {
+		if i%2 == 0 {
+			fmt.Fprint(w, " ")
+		}
+		if i < len(line) {
+			fmt.Fprint(w, colorize(fmt.Sprintf("%02x", line[i]), kindOfByte(line[i])))
+		} else {
+			fmt.Fprint(w, "  ")
+		}
+	}
+
+	// ASCII part
+	fmt.Fprint(w, "  ")
+	for _, b := range line {
+		kind := kindOfByte(b)
+		c := "."
+		if kind == hexdumpPrintable || b == ' ' {
+			c = string(b)
+		}
+		fmt.Fprint(w, colorize(c, kind))
+	}
+	fmt.Fprintln(w)
+}
+
+func noColor(arg interface{}, kind hexdumpByteKind) interface{} {
+	return arg
+}
+
+// printBinary prints body in the manner of mode if body looks binary.
+// It returns false without printing anything if body is not binary; the
+// returned reader must be used to read body in that case.
+func printBinary(w io.Writer, body io.Reader, contentType string, mode BinaryMode, colorize func(arg interface{}, kind hexdumpByteKind) interface{}) (io.Reader, bool, error) {
+	if mode == BinaryRaw {
+		return body, false, nil
+	}
+	body, binary, err := sniffBinary(body, contentType)
+	if err != nil || !binary {
+		return body, false, err
+	}
+	if mode == BinaryHexdump {
+		return nil, true, writeHexdump(w, body, colorize)
+	}
+	fmt.Fprint(w, binaryNotice)
+	return nil, true, nil
+}
@@ -0,0 +1,124 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	testCases := []struct {
+		title     string
+		chunk     []byte
+		truncated bool
+		expected  bool
+	}{
+		{title: "Empty", chunk: []byte{}, expected: false},
+		{title: "ASCII", chunk: []byte("hello\n"), expected: false},
+		{title: "UTF-8", chunk: []byte("こんにちは"), expected: false},
+		{title: "NUL", chunk: []byte("hello\x00world"), expected: true},
+		{title: "Invalid UTF-8", chunk: []byte{0x89, 'P', 'N', 'G'}, expected: true},
+		{title: "Character cut at the end of chunk", chunk: []byte("こんにちは")[:14], truncated: true, expected: false},
+		{title: "Character cut at the end of body", chunk: []byte("こんにちは")[:14], truncated: false, expected: true},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			actual := isBinary(tt.chunk, tt.truncated)
+			if actual != tt.expected {
+				t.Errorf("unexpected result: expected=%v, actual=%v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPlainPrinter_PrintBody_Binary(t *testing.T) {
+	binary := "\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00"
+	testCases := []struct {
+		title    string
+		mode     BinaryMode
+		body     string
+		expected string
+	}{
+		{
+			title:    "Raw",
+			mode:     BinaryRaw,
+			body:     binary,
+			expected: binary,
+		},
+		{
+			title:    "Notice",
+			mode:     BinaryNotice,
+			body:     binary,
+			expected: binaryNotice,
+		},
+		{
+			title:    "Notice (text body)",
+			mode:     BinaryNotice,
+			body:     "hello",
+			expected: "hello",
+		},
+		{
+			title: "Hexdump",
+			mode:  BinaryHexdump,
+			body:  binary,
+			expected: strings.Join([]string{
+				"00000000: 8950 4e47 0d0a 1a0a 0000 000d 4948 4452  .PNG........IHDR",
+				"00000010: 0000                                     ..",
+				"",
+			}, "\n"),
+		},
+		{
+			title:    "Hexdump (text body)",
+			mode:     BinaryHexdump,
+			body:     "hello",
+			expected: "hello",
+		},
+		{
+			title:    "Large body",
+			mode:     BinaryNotice,
+			body:     strings.Repeat("a", binarySniffLength) + "\x00",
+			expected: strings.Repeat("a", binarySniffLength) + "\x00",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var buffer bytes.Buffer
+			printer := NewPlainPrinterWithConfig(PlainPrinterConfig{Writer: &buffer, Binary: tt.mode})
+
+			// Exercise
+			if err := printer.PrintBody(strings.NewReader(tt.body), "application/octet-stream"); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%q\nactual=\n%q", tt.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestPrettyPrinter_PrintBody_Hexdump(t *testing.T) {
+	// Setup
+	var buffer bytes.Buffer
+	printer := NewPrettyPrinter(PrettyPrinterConfig{
+		Writer:      &buffer,
+		EnableColor: true,
+		Binary:      BinaryHexdump,
+	})
+
+	// Exercise
+	if err := printer.PrintBody(strings.NewReader("a \x00\xff"), "application/json"); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Verify
+	expected := "\x1b[34m00000000\x1b[0m: " +
+		"\x1b[33m61\x1b[0m\x1b[36m20\x1b[0m \x1b[37m00\x1b[0m\x1b[1;31mff\x1b[0m" +
+		strings.Repeat(" ", 6*5) + "  " +
+		"\x1b[33ma\x1b[0m\x1b[36m \x1b[0m\x1b[37m.\x1b[0m\x1b[1;31m.\x1b[0m\n"
+	if buffer.String() != expected {
+		t.Errorf("unexpected output: expected=\n%q\nactual=\n%q", expected, buffer.String())
+	}
+}
@@ -0,0 +1,139 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"mime"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func isMsgpack(mediaType string) bool {
+	return mediaType == "application/msgpack" || mediaType == "application/x-msgpack" ||
+		mediaType == "application/vnd.msgpack"
+}
+
+func isCBOR(mediaType string) bool {
+	return mediaType == "application/cbor" || strings.HasSuffix(mediaType, "+cbor")
+}
+
+// decodeBinaryJSON converts MessagePack and CBOR bodies into JSON so that they
+// are printed in the same way as JSON bodies. It returns the converted body
+// and its content type. Other bodies, including malformed ones, are returned
+// as they are.
+//
+// The order of map keys is not preserved; they are sorted.
+func decodeBinaryJSON(body io.Reader, contentType string) (io.Reader, string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !(isMsgpack(mediaType) || isCBOR(mediaType)) {
+		return body, contentType, nil
+	}
+
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "reading body")
+	}
+	original := bytes.NewReader(content)
+
+	var values []interface{}
+	if isMsgpack(mediaType) {
+		values, err = decodeMsgpack(content)
+	} else {
+		values, err = decodeCBOR(content)
+	}
+	if err != nil || len(values) == 0 {
+		return original, contentType, nil
+	}
+
+	var buffer bytes.Buffer
+	for _, v := range values {
+		encoded, err := json.Marshal(toJSONValue(v))
+		if err != nil {
+			return original, contentType, nil
+		}
+		buffer.Write(encoded)
+		buffer.WriteByte('\n')
+	}
+	return &buffer, "application/json", nil
+}
+
+// decodeMsgpack decodes all values in content (streams of values are allowed).
+func decodeMsgpack(content []byte) ([]interface{}, error) {
+	decoder := msgpack.NewDecoder(bytes.NewReader(content))
+	decoder.SetMapDecoder(func(d *msgpack.Decoder) (interface{}, error) {
+		return d.DecodeUntypedMap()
+	})
+	var values []interface{}
+	for {
+		v, err := decoder.DecodeInterface()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+}
+
+// decodeCBOR decodes all values in content (CBOR sequences are allowed).
+func decodeCBOR(content []byte) ([]interface{}, error) {
+	decoder := cbor.NewDecoder(bytes.NewReader(content))
+	var values []interface{}
+	for {
+		var v interface{}
+		err := decoder.Decode(&v)
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+}
+
+// toJSONValue converts v decoded from MessagePack or CBOR into a value that
+// can be marshaled into JSON. Map keys are stringified, and non-finite floats
+// (which JSON cannot represent) are converted into strings. Byte strings are
+// marshaled in base64.
+func toJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(toJSONValue(key))] = toJSONValue(value)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = toJSONValue(value)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, value := range v {
+			a[i] = toJSONValue(value)
+		}
+		return a
+	case float32:
+		return toJSONValue(float64(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Sprint(v)
+		}
+		return v
+	case cbor.Tag:
+		return map[string]interface{}{"tag": v.Number, "content": toJSONValue(v.Content)}
+	default:
+		return v
+	}
+}
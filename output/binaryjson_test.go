@@ -0,0 +1,100 @@
+package output
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestPrettyPrinter_PrintBody_BinaryJSON(t *testing.T) {
+	value := map[string]interface{}{
+		"id":    1,
+		"name":  "foo",
+		"tags":  []interface{}{"a", nil, true},
+		"bytes": []byte("hi"),
+		"nan":   math.NaN(),
+	}
+	msgpackBody, err := msgpack.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to marshal MessagePack: err=%+v", err)
+	}
+	cborBody, err := cbor.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to marshal CBOR: err=%+v", err)
+	}
+	intKeyBody, err := cbor.Marshal(map[int]string{1: "one"})
+	if err != nil {
+		t.Fatalf("failed to marshal CBOR: err=%+v", err)
+	}
+	expected := strings.Join([]string{
+		`{`,
+		`    "bytes": "aGk=",`,
+		`    "id": 1,`,
+		`    "name": "foo",`,
+		`    "nan": "NaN",`,
+		`    "tags": [`,
+		`        "a",`,
+		`        null,`,
+		`        true`,
+		`    ]`,
+		"}\n",
+	}, "\n")
+
+	testCases := []struct {
+		title       string
+		body        []byte
+		contentType string
+		expected    string
+	}{
+		{title: "MessagePack", body: msgpackBody, contentType: "application/msgpack", expected: expected},
+		{title: "MessagePack (x-msgpack)", body: msgpackBody, contentType: "application/x-msgpack", expected: expected},
+		{title: "CBOR", body: cborBody, contentType: "application/cbor", expected: expected},
+		{title: "CBOR with non-string keys", body: intKeyBody, contentType: "application/cbor", expected: "{\n    \"1\": \"one\"\n}\n"},
+		{title: "Multiple values", body: append([]byte{0x01}, 0x02), contentType: "application/msgpack", expected: "1\n2\n"},
+		{title: "Malformed", body: []byte{0x82, 0xa1}, contentType: "application/msgpack", expected: binaryNotice},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var buffer strings.Builder
+			printer := NewPrettyPrinter(PrettyPrinterConfig{
+				Writer: &buffer,
+				Binary: BinaryNotice,
+			})
+
+			// Exercise
+			if err := printer.PrintBody(bytes.NewReader(tt.body), tt.contentType); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s", tt.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestFilter_Apply_Msgpack(t *testing.T) {
+	body, err := msgpack.Marshal(map[string]interface{}{"items": []string{"foo", "bar"}})
+	if err != nil {
+		t.Fatalf("failed to marshal MessagePack: err=%+v", err)
+	}
+	filter, err := ParseFilter(".items[0]")
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	reader, err := filter.Apply(bytes.NewReader(body), "application/msgpack")
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	actual, _ := ioutil.ReadAll(reader)
+	if string(actual) != "\"foo\"\n" {
+		t.Errorf("unexpected result: %q", string(actual))
+	}
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
+)
+
+// charsetSniffLength is the size of the first chunk searched for charset
+// declarations of HTML and XML.
+const charsetSniffLength = 1024
+
+var (
+	htmlCharsetPattern = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([\w.:-]+)`)
+	xmlCharsetPattern  = regexp.MustCompile(`^(?:\xef\xbb\xbf)?\s*<\?xml[^>]+encoding\s*=\s*["']([\w.:-]+)["']`)
+)
+
+// decodeBody returns a reader that converts body into UTF-8.
+//
+// The encoding of body is determined by the charset parameter of contentType.
+// If the parameter is absent, <meta charset> of HTML and the encoding
+// declaration of XML are used. Bodies in unknown encodings are returned as is.
+func decodeBody(body io.Reader, contentType string) (io.Reader, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return body, nil
+	}
+
+	charset := params["charset"]
+	if charset == "" {
+		var pattern *regexp.Regexp
+		if isHTML(mediaType) {
+			pattern = htmlCharsetPattern
+		} else if isXML(mediaType) {
+			pattern = xmlCharsetPattern
+		} else {
+			return body, nil
+		}
+
+		var chunk []byte
+		chunk, body, err = peekBody(body, charsetSniffLength)
+		if err != nil {
+			return nil, err
+		}
+		m := pattern.FindSubmatch(chunk)
+		if m == nil {
+			return body, nil
+		}
+		charset = string(m[1])
+	}
+
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return body, nil
+	}
+	if name, _ := htmlindex.Name(encoding); name == "utf-8" {
+		return body, nil
+	}
+	return transform.NewReader(body, encoding.NewDecoder()), nil
+}
+
+// WithCharset returns contentType whose charset parameter is replaced with charset.
+func WithCharset(contentType string, charset string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// Content-Type is missing or malformed
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	params["charset"] = charset
+	return mime.FormatMediaType(mediaType, params)
+}
+
+// peekBody reads the first n bytes of body. The returned reader yields the
+// whole body including the first chunk.
+func peekBody(body io.Reader, n int) ([]byte, io.Reader, error) {
+	chunk := make([]byte, n)
+	n, err := io.ReadFull(body, chunk)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, nil, errors.Wrap(err, "reading body")
+	}
+	chunk = chunk[:n]
+	return chunk, io.MultiReader(bytes.NewReader(chunk), body), nil
+}
+
+func isHTML(mediaType string) bool {
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+func isXML(mediaType string) bool {
+	return mediaType == "text/xml" || mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml")
+}
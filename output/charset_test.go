@@ -0,0 +1,151 @@
+package output
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBody(t *testing.T) {
+	testCases := []struct {
+		title       string
+		contentType string
+		body        string
+		expected    string
+	}{
+		{
+			title:       "UTF-8",
+			contentType: "text/plain; charset=utf-8",
+			body:        "こんにちは",
+			expected:    "こんにちは",
+		},
+		{
+			title:       "No charset",
+			contentType: "text/plain",
+			body:        "こんにちは",
+			expected:    "こんにちは",
+		},
+		{
+			title:       "Shift_JIS",
+			contentType: "text/html; charset=Shift_JIS",
+			body:        "\x82\xb1\x82\xf1\x82\xc9\x82\xbf\x82\xcd",
+			expected:    "こんにちは",
+		},
+		{
+			title:       "EUC-JP",
+			contentType: "application/json; charset=EUC-JP",
+			body:        "\"\xa4\xb3\xa4\xf3\xa4\xcb\xa4\xc1\xa4\xcf\"",
+			expected:    `"こんにちは"`,
+		},
+		{
+			title:       "Unknown charset",
+			contentType: "text/plain; charset=unknown",
+			body:        "\x82\xb1",
+			expected:    "\x82\xb1",
+		},
+		{
+			title:       "HTML meta charset",
+			contentType: "text/html",
+			body:        "<html><head><meta charset=\"Shift_JIS\"></head>\x82\xb1</html>",
+			expected:    "<html><head><meta charset=\"Shift_JIS\"></head>こ</html>",
+		},
+		{
+			title:       "HTML meta http-equiv",
+			contentType: "text/html",
+			body:        "<meta http-equiv=\"Content-Type\" content=\"text/html; charset=euc-jp\">\xa4\xb3",
+			expected:    "<meta http-equiv=\"Content-Type\" content=\"text/html; charset=euc-jp\">こ",
+		},
+		{
+			title:       "XML declaration",
+			contentType: "application/xml",
+			body:        "<?xml version=\"1.0\" encoding=\"Shift_JIS\"?>\n<a>\x82\xb1</a>",
+			expected:    "<?xml version=\"1.0\" encoding=\"Shift_JIS\"?>\n<a>こ</a>",
+		},
+		{
+			title:       "charset parameter takes precedence",
+			contentType: "text/html; charset=EUC-JP",
+			body:        "<meta charset=\"Shift_JIS\">\xa4\xb3",
+			expected:    "<meta charset=\"Shift_JIS\">こ",
+		},
+		{
+			title:       "meta charset is ignored in non-HTML",
+			contentType: "text/plain",
+			body:        "<meta charset=\"Shift_JIS\">\x82\xb1",
+			expected:    "<meta charset=\"Shift_JIS\">\x82\xb1",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			r, err := decodeBody(strings.NewReader(tt.body), tt.contentType)
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			actual, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if string(actual) != tt.expected {
+				t.Errorf("unexpected body: expected=%q, actual=%q", tt.expected, string(actual))
+			}
+		})
+	}
+}
+
+func TestWithCharset(t *testing.T) {
+	testCases := []struct {
+		contentType string
+		charset     string
+		expected    string
+	}{
+		{contentType: "text/html", charset: "Shift_JIS", expected: "text/html; charset=Shift_JIS"},
+		{contentType: "text/html; charset=utf-8", charset: "EUC-JP", expected: "text/html; charset=EUC-JP"},
+		{contentType: "", charset: "EUC-JP", expected: "text/plain; charset=EUC-JP"},
+	}
+	for _, tt := range testCases {
+		actual := WithCharset(tt.contentType, tt.charset)
+		if actual != tt.expected {
+			t.Errorf("unexpected content type: contentType=%q, expected=%q, actual=%q", tt.contentType, tt.expected, actual)
+		}
+	}
+}
+
+func TestPrettyPrinter_PrintBody_Charset(t *testing.T) {
+	// Setup
+	var buffer strings.Builder
+	printer := NewPrettyPrinter(PrettyPrinterConfig{
+		Writer: &buffer,
+		Binary: BinaryNotice,
+	})
+
+	// Exercise
+	// Shift_JIS text is not valid UTF-8, but it must not be regarded as binary.
+	body := "{\"greeting\": \"\x82\xb1\x82\xf1\x82\xc9\x82\xbf\x82\xcd\"}"
+	if err := printer.PrintBody(strings.NewReader(body), "application/json; charset=Shift_JIS"); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Verify
+	expected := "{\n    \"greeting\": \"こんにちは\"\n}\n"
+	if buffer.String() != expected {
+		t.Errorf("unexpected output: expected=%q, actual=%q", expected, buffer.String())
+	}
+}
+
+func TestPlainPrinter_PrintBody_Charset(t *testing.T) {
+	body := "<meta charset=\"Shift_JIS\"><p>\x82\xb1\x82\xf1\x82\xc9\x82\xbf\x82\xcd</p>"
+	for _, binary := range []BinaryMode{BinaryRaw, BinaryNotice} {
+		// Setup
+		var buffer strings.Builder
+		printer := NewPlainPrinterWithConfig(PlainPrinterConfig{Writer: &buffer, Binary: binary})
+
+		// Exercise
+		if err := printer.PrintBody(strings.NewReader(body), "text/html; charset=Shift_JIS"); err != nil {
+			t.Fatalf("unexpected error: err=%+v", err)
+		}
+
+		// Verify: the bytes are written as they are
+		if buffer.String() != body {
+			t.Errorf("unexpected output (binary=%v): expected=%q, actual=%q", binary, body, buffer.String())
+		}
+	}
+}
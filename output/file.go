@@ -3,6 +3,7 @@ package output
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
@@ -11,12 +12,15 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/vbauerster/mpb/v5"
 	"github.com/vbauerster/mpb/v5/decor"
 )
 
 type FileWriter struct {
 	fullPath string
+	resume   bool  // resume a partial download (--continue)
+	offset   int64 // size of the partial file to resume from
 }
 
 func NewFileWriter(url *url.URL, options *Options) *FileWriter {
@@ -28,12 +32,14 @@ func NewFileWriter(url *url.URL, options *Options) *FileWriter {
 		fullPath = options.OutputFile
 	}
 
-	if !options.Overwrite {
+	// The existing file is resumed with --continue
+	if !options.Overwrite && !options.Continue {
 		fullPath = makeNonOverlappingFilename(fullPath)
 	}
 
 	return &FileWriter{
 		fullPath: fullPath,
+		resume:   options.Continue,
 	}
 }
 
@@ -59,19 +65,122 @@ func makeNonOverlappingFilename(path string) string {
 	return path
 }
 
+// resumePath returns the path of the file that keeps the validator (ETag or
+// Last-Modified) of a download until it completes. It is used to verify that
+// the resource has not changed when the partial file is resumed.
+func (f *FileWriter) resumePath() string {
+	return f.fullPath + ".resume"
+}
+
+// resumeValidator returns the validator in header that can be used in If-Range.
+// Weak ETags cannot be used, in which case Last-Modified is used instead.
+func resumeValidator(header http.Header) string {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return header.Get("Last-Modified")
+}
+
+// AddResumeHeaders adds Range and If-Range to header to resume the partial
+// file. It does nothing unless resuming. The whole file is requested again if
+// the validator of the partial file is not known since whether the resource
+// has changed cannot be verified.
+func (f *FileWriter) AddResumeHeaders(header http.Header) error {
+	if !f.resume {
+		return nil
+	}
+	info, err := os.Stat(f.fullPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "checking the partial file")
+	}
+	f.offset = info.Size()
+	if f.offset == 0 {
+		return nil
+	}
+
+	validator, err := ioutil.ReadFile(f.resumePath())
+	if os.IsNotExist(err) || (err == nil && len(validator) == 0) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "reading the validator of the partial file")
+	}
+	// The server sends the whole resource if it has changed
+	header.Set("Range", fmt.Sprintf("bytes=%d-", f.offset))
+	header.Set("If-Range", string(validator))
+	return nil
+}
+
+// Resumes reports whether resp continues the partial file.
+func (f *FileWriter) Resumes(resp *http.Response) bool {
+	return f.offset > 0 && resp.StatusCode == http.StatusPartialContent
+}
+
+// Offset returns the size of the partial file to resume from.
+func (f *FileWriter) Offset() int64 {
+	return f.offset
+}
+
+// IsComplete reports whether resp tells that the partial file is already
+// complete (416 Range Not Satisfiable).
+func (f *FileWriter) IsComplete(resp *http.Response) (bool, error) {
+	if f.offset == 0 || resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		return false, nil
+	}
+	// Content-Range is "bytes */<complete length>"
+	if total, ok := parseContentRangeLength(resp.Header.Get("Content-Range")); ok && total != f.offset {
+		return false, errors.Errorf("range not satisfiable: %s has %d bytes but the resource has %d bytes", f.fullPath, f.offset, total)
+	}
+	if err := f.removeResumeFile(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (f *FileWriter) Download(resp *http.Response) error {
+	// Append to the partial file on 206, and start over on 200 (e.g. the
+	// resource has changed). Other responses such as errors must not replace it.
+	if f.offset > 0 && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return errors.Errorf("cannot resume the download of %s (the partial file is kept): %s", f.fullPath, resp.Status)
+	}
+
 	// Create new progress bar
 	pb := mpb.New(mpb.WithWidth(60))
 
-	// Create file
-	file, err := os.Create(f.fullPath)
+	var file *os.File
+	var err error
+	total := resp.ContentLength
+	if f.Resumes(resp) {
+		if start, ok := parseContentRangeStart(resp.Header.Get("Content-Range")); !ok || start != f.offset {
+			return errors.Errorf("unexpected Content-Range to resume from byte %d: %s", f.offset, resp.Header.Get("Content-Range"))
+		}
+		file, err = os.OpenFile(f.fullPath, os.O_WRONLY|os.O_APPEND, 0666)
+		if total >= 0 {
+			total += f.offset
+		}
+	} else {
+		file, err = os.Create(f.fullPath)
+	}
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
+	// Keep the validator until the download completes so that it can be
+	// resumed with --continue if interrupted
+	if validator := resumeValidator(resp.Header); validator != "" {
+		if err := ioutil.WriteFile(f.resumePath(), []byte(validator), 0666); err != nil {
+			return errors.Wrap(err, "saving the validator of the download")
+		}
+	} else if err := f.removeResumeFile(); err != nil {
+		return err
+	}
+
 	// Parameters of th new progress bar
-	bar := pb.AddBar(resp.ContentLength,
+	bar := pb.AddBar(total,
 		mpb.PrependDecorators(
 			decor.CountersKiloByte("% .2f / % .2f "),
 			decor.AverageSpeed(decor.UnitKB, "(% .2f)"),
@@ -86,6 +195,9 @@ func (f *FileWriter) Download(resp *http.Response) error {
 			),
 		),
 	)
+	if f.Resumes(resp) {
+		bar.SetCurrent(f.offset)
+	}
 
 	// Update progress bar while writing file
 	_, err = io.Copy(file, bar.ProxyReader(resp.Body))
@@ -95,9 +207,49 @@ func (f *FileWriter) Download(resp *http.Response) error {
 
 	pb.Wait()
 
+	return f.removeResumeFile()
+}
+
+// removeResumeFile removes the file made by resumePath if it exists.
+func (f *FileWriter) removeResumeFile() error {
+	if err := os.Remove(f.resumePath()); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "removing the validator of the download")
+	}
 	return nil
 }
 
+// parseContentRangeStart returns the first byte position of Content-Range
+// like "bytes 100-199/200".
+func parseContentRangeStart(contentRange string) (int64, bool) {
+	if !strings.HasPrefix(contentRange, "bytes ") {
+		return 0, false
+	}
+	spec := strings.TrimPrefix(contentRange, "bytes ")
+	dash := strings.Index(spec, "-")
+	if dash < 0 {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(spec[:dash], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
+
+// parseContentRangeLength returns the complete length of Content-Range like
+// "bytes */200". It returns false if the length is unknown ("*").
+func parseContentRangeLength(contentRange string) (int64, bool) {
+	slash := strings.LastIndex(contentRange, "/")
+	if !strings.HasPrefix(contentRange, "bytes ") || slash < 0 {
+		return 0, false
+	}
+	length, err := strconv.ParseInt(contentRange[slash+1:], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return length, true
+}
+
 func (f *FileWriter) Filename() string {
 	return filepath.Base(f.fullPath)
 }
@@ -0,0 +1,289 @@
+package output
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestFileWriter_Continue(t *testing.T) {
+	// Setup
+	const content = "0123456789abcdefghij"
+	etag := `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "data", time.Time{}, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "httpie-go-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: err=%+v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		title          string
+		partial        string // content of the partial file; no file if empty
+		savedETag      string // content of the resume file; no file if empty
+		expectedRange  string
+		expectedStatus int
+		complete       bool
+	}{
+		{
+			title:          "No partial file",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			title:          "Partial file",
+			partial:        content[:5],
+			savedETag:      etag,
+			expectedRange:  "bytes=5-",
+			expectedStatus: http.StatusPartialContent,
+		},
+		{
+			title:          "Partial file of another version",
+			partial:        "XXXXX",
+			savedETag:      `"v0"`,
+			expectedRange:  "bytes=5-",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			title:          "Partial file without validator",
+			partial:        "XXXXX",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			title:          "Complete file",
+			partial:        content,
+			savedETag:      etag,
+			expectedRange:  "bytes=20-",
+			expectedStatus: http.StatusRequestedRangeNotSatisfiable,
+			complete:       true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			path := filepath.Join(dir, "data")
+			os.Remove(path)
+			os.Remove(path + ".resume")
+			if tt.partial != "" {
+				if err := ioutil.WriteFile(path, []byte(tt.partial), 0666); err != nil {
+					t.Fatalf("failed to write partial file: err=%+v", err)
+				}
+			}
+			if tt.savedETag != "" {
+				if err := ioutil.WriteFile(path+".resume", []byte(tt.savedETag), 0666); err != nil {
+					t.Fatalf("failed to write resume file: err=%+v", err)
+				}
+			}
+
+			// Exercise
+			u, _ := url.Parse(server.URL + "/data")
+			file := NewFileWriter(u, &Options{OutputFile: path, Continue: true})
+			request, _ := http.NewRequest("GET", u.String(), nil)
+			if err := file.AddResumeHeaders(request.Header); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if actual := request.Header.Get("Range"); actual != tt.expectedRange {
+				t.Errorf("unexpected Range: expected=%q, actual=%q", tt.expectedRange, actual)
+			}
+			if actual := request.Header.Get("If-Range"); actual != tt.savedETag {
+				t.Errorf("unexpected If-Range: expected=%q, actual=%q", tt.savedETag, actual)
+			}
+			resp, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("unexpected status: expected=%d, actual=%d", tt.expectedStatus, resp.StatusCode)
+			}
+
+			complete, err := file.IsComplete(resp)
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if complete != tt.complete {
+				t.Errorf("unexpected result of IsComplete: expected=%v, actual=%v", tt.complete, complete)
+			}
+			if !complete {
+				if err := file.Download(resp); err != nil {
+					t.Fatalf("unexpected error: err=%+v", err)
+				}
+			}
+
+			// Verify
+			actual, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read file: err=%+v", err)
+			}
+			if string(actual) != content {
+				t.Errorf("unexpected content: expected=%q, actual=%q", content, actual)
+			}
+			if _, err := os.Stat(path + ".resume"); !os.IsNotExist(err) {
+				t.Errorf("resume file should be removed after the download completes")
+			}
+		})
+	}
+}
+
+func TestFileWriter_Continue_ErrorResponse(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "httpie-go-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: err=%+v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data")
+	const partial = "01234"
+	if err := ioutil.WriteFile(path, []byte(partial), 0666); err != nil {
+		t.Fatalf("failed to write partial file: err=%+v", err)
+	}
+
+	// Exercise
+	u, _ := url.Parse(server.URL + "/data")
+	file := NewFileWriter(u, &Options{OutputFile: path, Continue: true})
+	request, _ := http.NewRequest("GET", u.String(), nil)
+	if err := file.AddResumeHeaders(request.Header); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	defer resp.Body.Close()
+	if complete, err := file.IsComplete(resp); err != nil || complete {
+		t.Fatalf("unexpected result of IsComplete: complete=%v, err=%+v", complete, err)
+	}
+	if err := file.Download(resp); err == nil {
+		t.Errorf("error expected for %s", resp.Status)
+	}
+
+	// Verify
+	actual, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: err=%+v", err)
+	}
+	if string(actual) != partial {
+		t.Errorf("partial file should be kept: expected=%q, actual=%q", partial, actual)
+	}
+}
+
+// brokenReader returns an error after its content as if the connection is lost.
+type brokenReader struct {
+	io.Reader
+}
+
+func (r brokenReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection lost")
+	}
+	return n, err
+}
+
+func TestFileWriter_Download_Interrupted(t *testing.T) {
+	// Setup
+	dir, err := ioutil.TempDir("", "httpie-go-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: err=%+v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data")
+	const lastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+	resp := &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Etag": {`W/"v1"`}, "Last-Modified": {lastModified}},
+		ContentLength: 20,
+		Body:          ioutil.NopCloser(brokenReader{strings.NewReader("01234")}),
+	}
+
+	// Exercise
+	u, _ := url.Parse("http://example.com/data")
+	file := NewFileWriter(u, &Options{OutputFile: path, Overwrite: true})
+	if err := file.Download(resp); err == nil {
+		t.Fatalf("error expected when the connection is lost")
+	}
+	resumed := NewFileWriter(u, &Options{OutputFile: path, Continue: true})
+	header := http.Header{}
+	if err := resumed.AddResumeHeaders(header); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Verify
+	if actual := header.Get("Range"); actual != "bytes=5-" {
+		t.Errorf("unexpected Range: expected=%q, actual=%q", "bytes=5-", actual)
+	}
+	if actual := header.Get("If-Range"); actual != lastModified {
+		t.Errorf("unexpected If-Range: expected=%q, actual=%q", lastModified, actual)
+	}
+}
+
+func TestResumeValidator(t *testing.T) {
+	testCases := []struct {
+		header   http.Header
+		expected string
+	}{
+		{header: http.Header{"Etag": {`"v1"`}, "Last-Modified": {"Mon, 02 Jan 2006 15:04:05 GMT"}}, expected: `"v1"`},
+		{header: http.Header{"Etag": {`W/"v1"`}, "Last-Modified": {"Mon, 02 Jan 2006 15:04:05 GMT"}}, expected: "Mon, 02 Jan 2006 15:04:05 GMT"},
+		{header: http.Header{"Etag": {`W/"v1"`}}, expected: ""},
+		{header: http.Header{}, expected: ""},
+	}
+	for _, tt := range testCases {
+		if actual := resumeValidator(tt.header); actual != tt.expected {
+			t.Errorf("unexpected validator of %v: expected=%q, actual=%q", tt.header, tt.expected, actual)
+		}
+	}
+}
+
+func TestFileWriter_IsComplete_LengthMismatch(t *testing.T) {
+	file := &FileWriter{fullPath: "data", resume: true, offset: 30}
+	resp := &http.Response{
+		StatusCode: http.StatusRequestedRangeNotSatisfiable,
+		Header:     http.Header{"Content-Range": {"bytes */20"}},
+	}
+	if _, err := file.IsComplete(resp); err == nil {
+		t.Errorf("error expected when the file is larger than the resource")
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	testCases := []struct {
+		contentRange   string
+		expectedStart  int64
+		startOK        bool
+		expectedLength int64
+		lengthOK       bool
+	}{
+		{contentRange: "bytes 100-199/200", expectedStart: 100, startOK: true, expectedLength: 200, lengthOK: true},
+		{contentRange: "bytes 0-9/*", expectedStart: 0, startOK: true},
+		{contentRange: "bytes */200", expectedLength: 200, lengthOK: true},
+		{contentRange: "items 0-9/10"},
+		{contentRange: ""},
+	}
+	for _, tt := range testCases {
+		start, ok := parseContentRangeStart(tt.contentRange)
+		if ok != tt.startOK || start != tt.expectedStart {
+			t.Errorf("unexpected start of %q: expected=(%d, %v), actual=(%d, %v)", tt.contentRange, tt.expectedStart, tt.startOK, start, ok)
+		}
+		length, ok := parseContentRangeLength(tt.contentRange)
+		if ok != tt.lengthOK || length != tt.expectedLength {
+			t.Errorf("unexpected length of %q: expected=(%d, %v), actual=(%d, %v)", tt.contentRange, tt.expectedLength, tt.lengthOK, length, ok)
+		}
+	}
+}
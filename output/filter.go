@@ -0,0 +1,563 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Filter is a compiled expression of --filter.
+//
+// It supports a subset of jq:
+//
+//	.  .foo  ."foo"  .["foo"]  .[0]  .[-1]  .[2:4]  .[]  ..  .foo?  .a, .b  .items[] | .id
+//
+// and JSONPath, if the expression starts with '$':
+//
+//	$.foo  $['foo']  $[0]  $[2:4]  $[*]  $.*  $..foo
+type Filter struct {
+	expr string
+	// Stages are connected by '|'. Paths in a stage are connected by ','.
+	stages [][]filterPath
+}
+
+type filterPath []filterStep
+
+type filterStepKind int
+
+const (
+	fieldStep filterStepKind = iota
+	indexStep
+	sliceStep
+	iterateStep
+	recurseStep
+)
+
+type filterStep struct {
+	kind     filterStepKind
+	name     string // used by fieldStep
+	index    int    // used by indexStep
+	from     *int   // used by sliceStep
+	to       *int   // used by sliceStep
+	optional bool   // suppress errors (`?` in jq)
+	// selects nothing instead of null for missing keys and indices (JSONPath)
+	skipMissing bool
+}
+
+type filterSyntaxError struct {
+	expr    string
+	pos     int
+	message string
+}
+
+func (e *filterSyntaxError) Error() string {
+	return "invalid filter expression at position " + strconv.Itoa(e.pos+1) + " (" + e.expr + "): " + e.message
+}
+
+// ParseFilter compiles a jq-like or JSONPath expression.
+func ParseFilter(expr string) (*Filter, error) {
+	p := &filterParser{expr: expr}
+	p.skipSpaces()
+	if p.eof() {
+		return nil, p.error("expression is empty")
+	}
+
+	var stages [][]filterPath
+	if p.peek() == '$' {
+		path, err := p.parseJSONPath()
+		if err != nil {
+			return nil, err
+		}
+		stages = [][]filterPath{{path}}
+	} else {
+		for {
+			stage, err := p.parseStage()
+			if err != nil {
+				return nil, err
+			}
+			stages = append(stages, stage)
+			if p.eof() {
+				break
+			}
+			if p.peek() != '|' {
+				return nil, p.error("unexpected character: " + string(p.peek()))
+			}
+			p.pos++
+			p.skipSpaces()
+		}
+	}
+	if !p.eof() {
+		return nil, p.error("unexpected character: " + string(p.peek()))
+	}
+	return &Filter{expr: expr, stages: stages}, nil
+}
+
+type filterParser struct {
+	expr string
+	pos  int
+}
+
+func (p *filterParser) eof() bool {
+	return p.pos >= len(p.expr)
+}
+
+func (p *filterParser) peek() byte {
+	return p.expr[p.pos]
+}
+
+func (p *filterParser) lookahead(s string) bool {
+	return strings.HasPrefix(p.expr[p.pos:], s)
+}
+
+func (p *filterParser) skipSpaces() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\n') {
+		p.pos++
+	}
+}
+
+func (p *filterParser) error(message string) error {
+	return errors.WithStack(&filterSyntaxError{expr: p.expr, pos: p.pos, message: message})
+}
+
+func (p *filterParser) parseStage() ([]filterPath, error) {
+	var stage []filterPath
+	for {
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		stage = append(stage, path)
+		p.skipSpaces()
+		if p.eof() || p.peek() != ',' {
+			return stage, nil
+		}
+		p.pos++
+		p.skipSpaces()
+	}
+}
+
+// parsePath parses a jq path such as `.foo[0]."bar"[]?`.
+func (p *filterParser) parsePath() (filterPath, error) {
+	if p.eof() || p.peek() != '.' {
+		return nil, p.error("path must start with '.'")
+	}
+
+	path := filterPath{}
+	first := true
+	for !p.eof() {
+		switch {
+		case p.lookahead(".."):
+			p.pos += 2
+			path = append(path, filterStep{kind: recurseStep})
+		case p.peek() == '.':
+			p.pos++
+			if p.eof() {
+				if !first {
+					return nil, p.error("field name is expected after '.'")
+				}
+				break
+			}
+			if isIdentifierStart(p.peek()) {
+				path = append(path, filterStep{kind: fieldStep, name: p.parseIdentifier()})
+			} else if p.peek() == '"' {
+				name, err := p.parseString()
+				if err != nil {
+					return nil, err
+				}
+				path = append(path, filterStep{kind: fieldStep, name: name})
+			} else if p.peek() != '[' && !first {
+				return nil, p.error("field name is expected after '.'")
+			}
+		case p.peek() == '[':
+			step, err := p.parseBracket(false)
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, step)
+		case p.peek() == '?':
+			if len(path) == 0 {
+				return nil, p.error("'?' must follow a field, an index or an iterator")
+			}
+			p.pos++
+			path[len(path)-1].optional = true
+		default:
+			return path, nil
+		}
+		first = false
+	}
+	return path, nil
+}
+
+// parseJSONPath parses a JSONPath such as `$.store.book[*].author`.
+func (p *filterParser) parseJSONPath() (filterPath, error) {
+	p.pos++ // skip '$'
+
+	path := filterPath{}
+	for !p.eof() && p.peek() != ' ' {
+		var steps []filterStep
+		switch {
+		case p.lookahead(".."):
+			p.pos += 2
+			steps = append(steps, filterStep{kind: recurseStep})
+			if !p.eof() && p.peek() != '[' {
+				step, err := p.parseJSONPathMember()
+				if err != nil {
+					return nil, err
+				}
+				steps = append(steps, step)
+			}
+		case p.peek() == '.':
+			p.pos++
+			step, err := p.parseJSONPathMember()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		case p.peek() == '[':
+			step, err := p.parseBracket(true)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+			// A slice of JSONPath selects elements rather than an array.
+			if step.kind == sliceStep {
+				steps = append(steps, filterStep{kind: iterateStep})
+			}
+		default:
+			return nil, p.error("unexpected character: " + string(p.peek()))
+		}
+		for _, step := range steps {
+			step.optional = true
+			step.skipMissing = true
+			path = append(path, step)
+		}
+	}
+	p.skipSpaces()
+	return path, nil
+}
+
+func (p *filterParser) parseJSONPathMember() (filterStep, error) {
+	if p.eof() {
+		return filterStep{}, p.error("member name is expected")
+	}
+	if p.peek() == '*' {
+		p.pos++
+		return filterStep{kind: iterateStep}, nil
+	}
+	if !isIdentifierStart(p.peek()) {
+		return filterStep{}, p.error("member name is expected")
+	}
+	return filterStep{kind: fieldStep, name: p.parseIdentifier()}, nil
+}
+
+// parseBracket parses `[]`, `[0]`, `[1:3]` or `["foo"]` (and `[*]`, `['foo']` for JSONPath).
+func (p *filterParser) parseBracket(jsonPath bool) (filterStep, error) {
+	p.pos++ // skip '['
+	p.skipSpaces()
+	if p.eof() {
+		return filterStep{}, p.error("unterminated '['")
+	}
+
+	var step filterStep
+	switch c := p.peek(); {
+	case c == ']' && !jsonPath:
+		step = filterStep{kind: iterateStep}
+	case c == '*' && jsonPath:
+		p.pos++
+		step = filterStep{kind: iterateStep}
+	case c == '"' || (c == '\'' && jsonPath):
+		name, err := p.parseString()
+		if err != nil {
+			return filterStep{}, err
+		}
+		step = filterStep{kind: fieldStep, name: name}
+	default:
+		from, hasFrom, err := p.parseInt()
+		if err != nil {
+			return filterStep{}, err
+		}
+		p.skipSpaces()
+		if !p.eof() && p.peek() == ':' {
+			p.pos++
+			p.skipSpaces()
+			to, hasTo, err := p.parseInt()
+			if err != nil {
+				return filterStep{}, err
+			}
+			step = filterStep{kind: sliceStep}
+			if hasFrom {
+				step.from = &from
+			}
+			if hasTo {
+				step.to = &to
+			}
+		} else if hasFrom {
+			step = filterStep{kind: indexStep, index: from}
+		} else {
+			return filterStep{}, p.error("index is expected")
+		}
+	}
+
+	p.skipSpaces()
+	if p.eof() || p.peek() != ']' {
+		return filterStep{}, p.error("']' is expected")
+	}
+	p.pos++
+	return step, nil
+}
+
+func (p *filterParser) parseInt() (int, bool, error) {
+	start := p.pos
+	if !p.eof() && p.peek() == '-' {
+		p.pos++
+	}
+	for !p.eof() && '0' <= p.peek() && p.peek() <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false, nil
+	}
+	n, err := strconv.Atoi(p.expr[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, false, p.error("invalid number")
+	}
+	return n, true, nil
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func (p *filterParser) parseIdentifier() string {
+	start := p.pos
+	for !p.eof() && (isIdentifierStart(p.peek()) || ('0' <= p.peek() && p.peek() <= '9')) {
+		p.pos++
+	}
+	return p.expr[start:p.pos]
+}
+
+// parseString parses a double-quoted JSON string, or a single-quoted string of JSONPath.
+func (p *filterParser) parseString() (string, error) {
+	start := p.pos
+	quote := p.peek()
+	p.pos++
+	for !p.eof() && p.peek() != quote {
+		if p.peek() == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.eof() {
+		p.pos = start
+		return "", p.error("unterminated string")
+	}
+	p.pos++
+
+	if quote == '\'' {
+		return strings.Replace(p.expr[start+1:p.pos-1], `\'`, `'`, -1), nil
+	}
+	var s string
+	if err := json.Unmarshal([]byte(p.expr[start:p.pos]), &s); err != nil {
+		p.pos = start
+		return "", p.error("invalid string")
+	}
+	return s, nil
+}
+
+// Apply applies the filter to a JSON body and returns the selected values,
+// each of which is encoded in a line.
+func (f *Filter) Apply(body io.Reader, contentType string) (io.Reader, error) {
+	body, contentType, err := decodeBinaryJSON(body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	if !isJSON(contentType) {
+		return nil, errors.Errorf("cannot apply --filter to non-JSON body (Content-Type: %s)", contentType)
+	}
+	body, err = decodeBody(body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading body")
+	}
+	toks, err := newTokenBuffer(json.NewDecoder(bytes.NewReader(content)))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot apply --filter to malformed JSON")
+	}
+
+	var inputs [][]json.Token
+	for pos := 0; pos < len(toks.tokens); {
+		end, ok := valueEnd(toks.tokens, pos)
+		if !ok {
+			return nil, errors.New("cannot apply --filter to malformed JSON")
+		}
+		inputs = append(inputs, toks.tokens[pos:end])
+		pos = end
+	}
+
+	results, err := f.apply(inputs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "applying filter '%s'", f.expr)
+	}
+	if len(results) == 0 {
+		return nil, errors.Errorf("filter '%s' selected nothing", f.expr)
+	}
+
+	var buffer bytes.Buffer
+	for _, result := range results {
+		encodeTokens(&buffer, result)
+		buffer.WriteString("\n")
+	}
+	return &buffer, nil
+}
+
+func (f *Filter) apply(values [][]json.Token) ([][]json.Token, error) {
+	for _, stage := range f.stages {
+		var next [][]json.Token
+		for _, value := range values {
+			for _, path := range stage {
+				results, err := path.apply(value)
+				if err != nil {
+					return nil, err
+				}
+				next = append(next, results...)
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func (path filterPath) apply(value []json.Token) ([][]json.Token, error) {
+	values := [][]json.Token{value}
+	for _, step := range path {
+		var next [][]json.Token
+		for _, value := range values {
+			results, err := step.apply(value)
+			if err != nil {
+				if step.optional {
+					continue
+				}
+				return nil, err
+			}
+			next = append(next, results...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+var nullValue = []json.Token{nil}
+
+func (step *filterStep) missing() [][]json.Token {
+	if step.skipMissing {
+		return nil
+	}
+	return [][]json.Token{nullValue}
+}
+
+func (step *filterStep) apply(value []json.Token) ([][]json.Token, error) {
+	kind := kindOfValue(value)
+	switch step.kind {
+	case fieldStep:
+		switch kind {
+		case "object":
+			keys, values := children(value)
+			for i, key := range keys {
+				if key == step.name {
+					return [][]json.Token{values[i]}, nil
+				}
+			}
+			return step.missing(), nil
+		case "null":
+			return step.missing(), nil
+		default:
+			return nil, errors.Errorf("cannot index %s with \"%s\"", kind, step.name)
+		}
+	case indexStep:
+		switch kind {
+		case "array":
+			_, elements := children(value)
+			index := step.index
+			if index < 0 {
+				index += len(elements)
+			}
+			if index < 0 || len(elements) <= index {
+				return step.missing(), nil
+			}
+			return [][]json.Token{elements[index]}, nil
+		case "null":
+			return step.missing(), nil
+		default:
+			return nil, errors.Errorf("cannot index %s with number", kind)
+		}
+	case sliceStep:
+		switch kind {
+		case "array":
+			_, elements := children(value)
+			from, to := sliceRange(step.from, step.to, len(elements))
+			result := []json.Token{json.Delim('[')}
+			for _, element := range elements[from:to] {
+				result = append(result, element...)
+			}
+			result = append(result, json.Delim(']'))
+			return [][]json.Token{result}, nil
+		case "null":
+			return step.missing(), nil
+		default:
+			return nil, errors.Errorf("cannot slice %s", kind)
+		}
+	case iterateStep:
+		switch kind {
+		case "array", "object":
+			_, values := children(value)
+			return values, nil
+		default:
+			return nil, errors.Errorf("cannot iterate over %s", kind)
+		}
+	case recurseStep:
+		results := [][]json.Token{value}
+		if kind == "array" || kind == "object" {
+			_, values := children(value)
+			for _, child := range values {
+				descendants, _ := step.apply(child)
+				results = append(results, descendants...)
+			}
+		}
+		return results, nil
+	default:
+		return nil, errors.Errorf("[BUG] unknown filter step: %v", step.kind)
+	}
+}
+
+func sliceRange(from, to *int, length int) (int, int) {
+	normalize := func(i *int, defaultValue int) int {
+		if i == nil {
+			return defaultValue
+		}
+		n := *i
+		if n < 0 {
+			n += length
+		}
+		if n < 0 {
+			return 0
+		}
+		if n > length {
+			return length
+		}
+		return n
+	}
+	f, t := normalize(from, 0), normalize(to, length)
+	if t < f {
+		t = f
+	}
+	return f, t
+}
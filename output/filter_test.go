@@ -0,0 +1,111 @@
+package output
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestFilter_Apply(t *testing.T) {
+	body := `{"items": [{"id": 1, "name": "foo", "tags": ["a"]}, {"id": 2, "name": "bar", "tags": []}], "total": 2.50, "next": null}`
+	testCases := []struct {
+		expr     string
+		body     string
+		expected string
+	}{
+		{expr: ".", expected: `{"items":[{"id":1,"name":"foo","tags":["a"]},{"id":2,"name":"bar","tags":[]}],"total":2.50,"next":null}` + "\n"},
+		{expr: ".total", expected: "2.50\n"},
+		{expr: `."total"`, expected: "2.50\n"},
+		{expr: `.["total"]`, expected: "2.50\n"},
+		{expr: ".missing", expected: "null\n"},
+		{expr: ".next.foo", expected: "null\n"},
+		{expr: ".items[0].name", expected: "\"foo\"\n"},
+		{expr: ".items[-1].id", expected: "2\n"},
+		{expr: ".items[5]", expected: "null\n"},
+		{expr: ".items[].name", expected: "\"foo\"\n\"bar\"\n"},
+		{expr: ".items[] | .id", expected: "1\n2\n"},
+		{expr: ".items[1:]", expected: `[{"id":2,"name":"bar","tags":[]}]` + "\n"},
+		{expr: ".items[:1][].id", expected: "1\n"},
+		{expr: ".total, .items[0].id", expected: "2.50\n1\n"},
+		{expr: ".items[].tags[]", expected: "\"a\"\n"},
+		{expr: ".[]?.id?", body: `[{"id": 1}, 2, {"id": 3}]`, expected: "1\n3\n"},
+		{expr: "..|.id?", expected: "null\n1\n2\nnull\n"},
+		{expr: "$.items[*].name", expected: "\"foo\"\n\"bar\"\n"},
+		{expr: "$.items[0]['name']", expected: "\"foo\"\n"},
+		{expr: "$..id", expected: "1\n2\n"},
+		{expr: "$.items[0:1].id", expected: "1\n"},
+		{expr: ".id", body: `{"id": 1}` + "\n" + `{"id": 2}`, expected: "1\n2\n"},
+		{expr: ".", body: `{"<>": "a&b"}`, expected: `{"<>":"a&b"}` + "\n"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.expr, func(t *testing.T) {
+			filter, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			b := body
+			if tt.body != "" {
+				b = tt.body
+			}
+			reader, err := filter.Apply(strings.NewReader(b), "application/json; charset=utf-8")
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			actual, _ := ioutil.ReadAll(reader)
+			if string(actual) != tt.expected {
+				t.Errorf("unexpected result: expected=%q, actual=%q", tt.expected, string(actual))
+			}
+		})
+	}
+}
+
+func TestFilter_ApplyError(t *testing.T) {
+	testCases := []struct {
+		title       string
+		expr        string
+		body        string
+		contentType string
+	}{
+		{title: "Selects nothing", expr: ".[]", body: `[]`, contentType: "application/json"},
+		{title: "JSONPath selects nothing", expr: "$.missing", body: `{}`, contentType: "application/json"},
+		{title: "Type mismatch", expr: ".foo", body: `[1]`, contentType: "application/json"},
+		{title: "Iterate over number", expr: ".[]", body: `1`, contentType: "application/json"},
+		{title: "Malformed JSON", expr: ".", body: `{"foo": `, contentType: "application/json"},
+		{title: "Not JSON", expr: ".", body: `<html></html>`, contentType: "text/html"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			filter, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if _, err := filter.Apply(strings.NewReader(tt.body), tt.contentType); err == nil {
+				t.Errorf("error expected but got nil")
+			}
+		})
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	exprs := []string{
+		"",
+		"foo",
+		".foo bar",
+		".[",
+		".[abc]",
+		`.["foo]`,
+		".foo.",
+		"?",
+		".foo |",
+		"$.foo[",
+		"$.",
+		"$foo",
+	}
+	for _, expr := range exprs {
+		if _, err := ParseFilter(expr); err == nil {
+			t.Errorf("error expected for %q but got nil", expr)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/url"
+	"strings"
+
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/pkg/errors"
+)
+
+// formPrinter renders decoded views of form request bodies.
+type formPrinter struct {
+	writer   io.Writer
+	palette  *HeaderPalette
+	colorize func(arg interface{}, color Color) interface{}
+	// printBody prints text contents of multipart parts to w.
+	printBody func(w io.Writer, body io.Reader, contentType string) error
+}
+
+// printForm prints application/x-www-form-urlencoded bodies as a list of
+// decoded fields, and multipart/form-data bodies as a summary of each part.
+// It returns false without printing anything if body is not such a form; the
+// returned reader must be used to read body in that case.
+func (f *formPrinter) printForm(body io.Reader, contentType string) (io.Reader, bool, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != "application/x-www-form-urlencoded" && mediaType != "multipart/form-data") {
+		return body, false, nil
+	}
+
+	if mediaType == "multipart/form-data" {
+		body, _, parts, ok, err := readMultipart(body, contentType)
+		if !ok || err != nil {
+			return body, false, err
+		}
+		return nil, true, f.printMultipartForm(parts)
+	}
+
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "reading body")
+	}
+	fields, err := parseURLEncoded(string(content), contentType)
+	if err != nil {
+		return bytes.NewReader(content), false, nil
+	}
+	for _, field := range fields {
+		fmt.Fprintf(f.writer, "%s%s%s\n",
+			f.colorize(tableCellEscaper.Replace(field[0]), f.palette.FieldName),
+			f.colorize("=", f.palette.FieldSeparator),
+			f.colorize(tableCellEscaper.Replace(field[1]), f.palette.FieldValue))
+	}
+	return nil, true, nil
+}
+
+// parseURLEncoded decodes name-value pairs of a URL-encoded form in order of
+// appearance. Names and values are decoded according to the charset parameter
+// of contentType.
+func parseURLEncoded(content string, contentType string) ([][2]string, error) {
+	var fields [][2]string
+	for _, pair := range strings.Split(content, "&") {
+		if pair == "" {
+			continue
+		}
+		var field [2]string
+		for i, s := range strings.SplitN(pair, "=", 2) {
+			unescaped, err := url.QueryUnescape(s)
+			if err != nil {
+				return nil, err
+			}
+			decoded, err := decodeBody(strings.NewReader(unescaped), contentType)
+			if err != nil {
+				return nil, err
+			}
+			b, err := ioutil.ReadAll(decoded)
+			if err != nil {
+				return nil, err
+			}
+			field[i] = string(b)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// printMultipartForm prints a summary line of each part followed by its
+// content. Binary contents are elided.
+func (f *formPrinter) printMultipartForm(parts []bodyPart) error {
+	for _, part := range parts {
+		_, params, _ := mime.ParseMediaType(part.header.Get("Content-Disposition"))
+		attributes := [][2]string{{"name", fmt.Sprintf("%q", params["name"])}}
+		if filename, ok := params["filename"]; ok {
+			attributes = append(attributes, [2]string{"filename", fmt.Sprintf("%q", filename)})
+		}
+		if contentType := part.header.Get("Content-Type"); contentType != "" {
+			attributes = append(attributes, [2]string{"content-type", contentType})
+		}
+		attributes = append(attributes, [2]string{"size", bytefmt.ByteSize(uint64(len(part.body)))})
+
+		fmt.Fprintf(f.writer, "%s", f.colorize("---", f.palette.FieldSeparator))
+		for i, attribute := range attributes {
+			separator := " "
+			if i > 0 {
+				separator = "; "
+			}
+			fmt.Fprintf(f.writer, "%s%s%s%s",
+				f.colorize(separator, f.palette.FieldSeparator),
+				f.colorize(attribute[0], f.palette.FieldName),
+				f.colorize("=", f.palette.FieldSeparator),
+				f.colorize(attribute[1], f.palette.FieldValue))
+		}
+		fmt.Fprintln(f.writer)
+
+		chunk := part.body
+		if len(chunk) > binarySniffLength {
+			chunk = chunk[:binarySniffLength]
+		}
+		if isBinary(chunk, len(part.body) > binarySniffLength) {
+			fmt.Fprintln(f.writer, "(binary data not shown)")
+			continue
+		}
+		tracker := &newlineTracker{writer: f.writer}
+		if err := f.printBody(tracker, bytes.NewReader(part.body), partContentType(part)); err != nil {
+			return err
+		}
+		if tracker.needsNewline() {
+			fmt.Fprintln(f.writer)
+		}
+	}
+	return nil
+}
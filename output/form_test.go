@@ -0,0 +1,111 @@
+package output
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+func TestPrinter_PrintRequestBody(t *testing.T) {
+	var multipartBody bytes.Buffer
+	writer := multipart.NewWriter(&multipartBody)
+	writer.SetBoundary("xyz")
+	field, _ := writer.CreateFormField("hello")
+	field.Write([]byte("🍺 world!"))
+	file, _ := writer.CreateFormFile("image", "a.png")
+	file.Write([]byte("\x89PNG\r\n\x1a\n\x00\x00"))
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="data"; filename="data.json"`)
+	h.Set("Content-Type", "application/json")
+	part, _ := writer.CreatePart(h)
+	part.Write([]byte(`{"id": 1}`))
+	writer.Close()
+
+	testCases := []struct {
+		title          string
+		body           string
+		contentType    string
+		expectedPlain  string
+		expectedPretty string
+	}{
+		{
+			title:          "URL-encoded",
+			body:           "foo=bar&name=%E5%B1%B1%E7%94%B0&empty=&novalue&multi=a%0Ab",
+			contentType:    "application/x-www-form-urlencoded; charset=utf-8",
+			expectedPlain:  "foo=bar\nname=山田\nempty=\nnovalue=\nmulti=a\\nb\n",
+			expectedPretty: "foo=bar\nname=山田\nempty=\nnovalue=\nmulti=a\\nb\n",
+		},
+		{
+			title:          "URL-encoded in Shift_JIS",
+			body:           "name=%8ER%93c",
+			contentType:    "application/x-www-form-urlencoded; charset=Shift_JIS",
+			expectedPlain:  "name=山田\n",
+			expectedPretty: "name=山田\n",
+		},
+		{
+			title:          "Malformed URL-encoded",
+			body:           "foo=%zz",
+			contentType:    "application/x-www-form-urlencoded",
+			expectedPlain:  "foo=%zz",
+			expectedPretty: "foo=%zz",
+		},
+		{
+			title:       "Multipart",
+			body:        multipartBody.String(),
+			contentType: writer.FormDataContentType(),
+			expectedPlain: strings.Join([]string{
+				`--- name="hello"; size=11B`,
+				`🍺 world!`,
+				`--- name="image"; filename="a.png"; content-type=application/octet-stream; size=10B`,
+				`(binary data not shown)`,
+				`--- name="data"; filename="data.json"; content-type=application/json; size=9B`,
+				`{"id": 1}`,
+				``,
+			}, "\n"),
+			expectedPretty: strings.Join([]string{
+				`--- name="hello"; size=11B`,
+				`🍺 world!`,
+				`--- name="image"; filename="a.png"; content-type=application/octet-stream; size=10B`,
+				`(binary data not shown)`,
+				`--- name="data"; filename="data.json"; content-type=application/json; size=9B`,
+				`{`,
+				`    "id": 1`,
+				`}`,
+				``,
+			}, "\n"),
+		},
+		{
+			title:          "Other bodies",
+			body:           `{"id": 1}`,
+			contentType:    "application/json",
+			expectedPlain:  `{"id": 1}`,
+			expectedPretty: "{\n    \"id\": 1\n}\n",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var plainBuffer, prettyBuffer strings.Builder
+			plain := NewPlainPrinter(&plainBuffer)
+			pretty := NewPrettyPrinter(PrettyPrinterConfig{Writer: &prettyBuffer})
+
+			// Exercise
+			if err := PrintRequestBody(plain, strings.NewReader(tt.body), tt.contentType); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if err := PrintRequestBody(pretty, strings.NewReader(tt.body), tt.contentType); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if plainBuffer.String() != tt.expectedPlain {
+				t.Errorf("unexpected output of PlainPrinter: expected=\n%s\nactual=\n%s", tt.expectedPlain, plainBuffer.String())
+			}
+			if prettyBuffer.String() != tt.expectedPretty {
+				t.Errorf("unexpected output of PrettyPrinter: expected=\n%s\nactual=\n%s", tt.expectedPretty, prettyBuffer.String())
+			}
+		})
+	}
+}
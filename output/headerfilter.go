@@ -0,0 +1,62 @@
+package output
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HeaderFilter selects header fields to print by glob patterns such as
+// "Content-*". Patterns prefixed with '!' exclude fields. If there is no
+// including pattern, all fields not excluded are printed.
+type HeaderFilter struct {
+	includes []string
+	excludes []string
+}
+
+// ParseHeaderFilter parses comma-separated patterns (e.g. "Content-*,!X-Amzn-*").
+// Patterns are case-insensitive.
+func ParseHeaderFilter(patterns string) (*HeaderFilter, error) {
+	f := &HeaderFilter{}
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		exclude := strings.HasPrefix(pattern, "!")
+		if exclude {
+			pattern = strings.TrimSpace(pattern[1:])
+		}
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Errorf("invalid header pattern: %s", pattern)
+		}
+		if exclude {
+			f.excludes = append(f.excludes, pattern)
+		} else {
+			f.includes = append(f.includes, pattern)
+		}
+	}
+	return f, nil
+}
+
+// Match reports whether the field name should be printed. A nil filter
+// matches all names.
+func (f *HeaderFilter) Match(name string) bool {
+	if f == nil {
+		return true
+	}
+	name = strings.ToLower(name)
+	matchAny := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+	if len(f.includes) > 0 && !matchAny(f.includes) {
+		return false
+	}
+	return !matchAny(f.excludes)
+}
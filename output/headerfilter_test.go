@@ -0,0 +1,75 @@
+package output
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHeaderFilter_Match(t *testing.T) {
+	testCases := []struct {
+		patterns string
+		name     string
+		expected bool
+	}{
+		{patterns: "Content-*", name: "Content-Type", expected: true},
+		{patterns: "Content-*", name: "content-length", expected: true},
+		{patterns: "Content-*", name: "Date", expected: false},
+		{patterns: "!X-Amzn-*", name: "X-Amzn-RequestId", expected: false},
+		{patterns: "!X-Amzn-*", name: "Date", expected: true},
+		{patterns: "Content-*,!Content-Length", name: "Content-Length", expected: false},
+		{patterns: "Content-*, !Content-Length", name: "Content-Type", expected: true},
+		{patterns: "Dat?,Server", name: "Date", expected: true},
+		{patterns: "Dat?,Server", name: "Server", expected: true},
+		{patterns: "Dat?,Server", name: "Vary", expected: false},
+	}
+	for _, tt := range testCases {
+		filter, err := ParseHeaderFilter(tt.patterns)
+		if err != nil {
+			t.Fatalf("unexpected error: err=%+v", err)
+		}
+		if actual := filter.Match(tt.name); actual != tt.expected {
+			t.Errorf("unexpected result of Match(%q) with %q: expected=%v, actual=%v", tt.name, tt.patterns, tt.expected, actual)
+		}
+	}
+}
+
+func TestParseHeaderFilter_Error(t *testing.T) {
+	if _, err := ParseHeaderFilter("X-[a"); err == nil {
+		t.Errorf("error expected for malformed pattern")
+	}
+}
+
+func TestPrinter_PrintHeader_HeaderFilter(t *testing.T) {
+	filter, err := ParseHeaderFilter("Content-*,X-*,!X-Amzn-*")
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	header := http.Header{
+		"Content-Type":     {"application/json"},
+		"Date":             {"Tue, 10 Mar 2020 12:00:00 GMT"},
+		"X-Amzn-Requestid": {"abc"},
+		"X-Request-Id":     {"def"},
+	}
+	expected := "Content-Type: application/json\nX-Request-Id: def\n\n"
+
+	printers := map[string]func(*strings.Builder) Printer{
+		"Plain": func(b *strings.Builder) Printer {
+			return NewPlainPrinterWithConfig(PlainPrinterConfig{Writer: b, HeaderFilter: filter})
+		},
+		"Pretty": func(b *strings.Builder) Printer {
+			return NewPrettyPrinter(PrettyPrinterConfig{Writer: b, HeaderFilter: filter})
+		},
+	}
+	for title, newPrinter := range printers {
+		t.Run(title, func(t *testing.T) {
+			var buffer strings.Builder
+			if err := newPrinter(&buffer).PrintHeader(header); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if buffer.String() != expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s", expected, buffer.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package output
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// minWrapWidth is the minimum width of wrapped header values. Values are not
+// wrapped on narrower terminals since they would be hard to read anyway.
+const minWrapWidth = 20
+
+// headerRune is a character of a header value with the URL it links to.
+type headerRune struct {
+	r   rune
+	url string // empty if not a hyperlink
+}
+
+// formatHeaderValue colorizes value and wraps it to fit in the terminal with
+// hanging indentation aligned after "name: ". URLs in Location,
+// Content-Location and Link are emitted as OSC 8 hyperlinks if enabled.
+func (p *PrettyPrinter) formatHeaderValue(name string, value string) string {
+	runes := headerRunes(name, value, p.hyperlinks)
+
+	indent := runewidth.StringWidth(name) + 2
+	var lines [][]headerRune
+	if width := p.terminalWidth - indent; p.terminalWidth > 0 && width >= minWrapWidth {
+		lines = wrapHeaderRunes(runes, width)
+	} else {
+		lines = [][]headerRune{runes}
+	}
+
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\n" + strings.Repeat(" ", indent))
+		}
+		// Consecutive characters with the same URL are written together
+		for start := 0; start < len(line); {
+			end := start
+			var text strings.Builder
+			for end < len(line) && line[end].url == line[start].url {
+				text.WriteRune(line[end].r)
+				end++
+			}
+			colored := fmt.Sprint(p.colorize(text.String(), p.headerPalette.FieldValue))
+			if u := line[start].url; u != "" {
+				colored = "\x1b]8;;" + u + "\x1b\\" + colored + "\x1b]8;;\x1b\\"
+			}
+			b.WriteString(colored)
+			start = end
+		}
+	}
+	return b.String()
+}
+
+// headerRunes splits value into characters and marks URLs to be hyperlinked.
+func headerRunes(name string, value string, hyperlinks bool) []headerRune {
+	runes := make([]headerRune, 0, len(value))
+	for _, r := range value {
+		runes = append(runes, headerRune{r: r})
+	}
+	if !hyperlinks {
+		return runes
+	}
+
+	link := func(start, end int) {
+		u := value[start:end]
+		if !isAbsoluteURL(u) {
+			return
+		}
+		// Convert byte offsets into rune offsets
+		from := len([]rune(value[:start]))
+		to := from + len([]rune(u))
+		for i := from; i < to; i++ {
+			runes[i].url = u
+		}
+	}
+	switch name {
+	case "Location", "Content-Location":
+		link(0, len(value))
+	case "Link":
+		for offset := 0; ; {
+			start := strings.Index(value[offset:], "<")
+			if start < 0 {
+				break
+			}
+			end := strings.Index(value[offset+start:], ">")
+			if end < 0 {
+				break
+			}
+			link(offset+start+1, offset+start+end)
+			offset += start + end + 1
+		}
+	}
+	return runes
+}
+
+func isAbsoluteURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// wrapHeaderRunes breaks runes into lines no wider than width at spaces.
+// Words longer than width are not broken.
+func wrapHeaderRunes(runes []headerRune, width int) [][]headerRune {
+	var lines [][]headerRune
+	var line []headerRune
+	lineWidth := 0
+	for len(runes) > 0 {
+		// A word continues until the next space, including the space
+		end := 0
+		wordWidth := 0
+		for end < len(runes) && runes[end].r != ' ' {
+			wordWidth += runewidth.RuneWidth(runes[end].r)
+			end++
+		}
+		word := runes[:end]
+		if end < len(runes) {
+			end++ // the space
+		}
+
+		if lineWidth > 0 && lineWidth+wordWidth > width {
+			lines = append(lines, trimTrailingSpace(line))
+			line, lineWidth = nil, 0
+		}
+		line = append(line, runes[:end]...)
+		lineWidth += wordWidth + (end - len(word))
+		runes = runes[end:]
+	}
+	return append(lines, trimTrailingSpace(line))
+}
+
+func trimTrailingSpace(line []headerRune) []headerRune {
+	for len(line) > 0 && line[len(line)-1].r == ' ' {
+		line = line[:len(line)-1]
+	}
+	return line
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPrettyPrinter_PrintHeader_Wrap(t *testing.T) {
+	testCases := []struct {
+		title         string
+		header        http.Header
+		terminalWidth int
+		hyperlinks    bool
+		expected      string
+	}{
+		{
+			title:         "Wrapped",
+			header:        http.Header{"Content-Security-Policy": {"default-src 'self'; img-src 'self' https://img.example.com; script-src 'self'"}},
+			terminalWidth: 60,
+			expected: strings.Join([]string{
+				"Content-Security-Policy: default-src 'self'; img-src 'self'",
+				"                         https://img.example.com; script-src",
+				"                         'self'",
+				"",
+				"",
+			}, "\n"),
+		},
+		{
+			title:         "Long word",
+			header:        http.Header{"X-Token": {"short " + strings.Repeat("a", 40) + " end"}},
+			terminalWidth: 30,
+			expected: strings.Join([]string{
+				"X-Token: short",
+				"         " + strings.Repeat("a", 40),
+				"         end",
+				"",
+				"",
+			}, "\n"),
+		},
+		{
+			title:         "Unknown width",
+			header:        http.Header{"Content-Security-Policy": {"default-src 'self'; img-src 'self' https://img.example.com"}},
+			terminalWidth: 0,
+			expected:      "Content-Security-Policy: default-src 'self'; img-src 'self' https://img.example.com\n\n",
+		},
+		{
+			title:      "Location",
+			header:     http.Header{"Location": {"https://example.com/a"}},
+			hyperlinks: true,
+			expected:   "Location: \x1b]8;;https://example.com/a\x1b\\https://example.com/a\x1b]8;;\x1b\\\n\n",
+		},
+		{
+			title:      "Relative Location",
+			header:     http.Header{"Location": {"/a"}},
+			hyperlinks: true,
+			expected:   "Location: /a\n\n",
+		},
+		{
+			title:         "Link",
+			header:        http.Header{"Link": {`<https://example.com/?page=2>; rel="next", <https://example.com/?page=9>; rel="last"`}},
+			terminalWidth: 50,
+			hyperlinks:    true,
+			expected: strings.Join([]string{
+				"Link: <\x1b]8;;https://example.com/?page=2\x1b\\https://example.com/?page=2\x1b]8;;\x1b\\>; rel=\"next\",",
+				"      <\x1b]8;;https://example.com/?page=9\x1b\\https://example.com/?page=9\x1b]8;;\x1b\\>; rel=\"last\"",
+				"",
+				"",
+			}, "\n"),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var buffer strings.Builder
+			printer := NewPrettyPrinter(PrettyPrinterConfig{
+				Writer:        &buffer,
+				Hyperlinks:    tt.hyperlinks,
+				TerminalWidth: tt.terminalWidth,
+			})
+
+			// Exercise
+			if err := printer.PrintHeader(tt.header); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%q\nactual=\n%q", tt.expected, buffer.String())
+			}
+		})
+	}
+}
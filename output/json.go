@@ -0,0 +1,132 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Helpers for JSON values represented as token sequences (see newTokenBuffer).
+
+func kindOfValue(value []json.Token) string {
+	switch v := value[0].(type) {
+	case json.Delim:
+		if v == '[' {
+			return "array"
+		}
+		return "object"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+// children returns the elements of an array, or the keys and the values of an
+// object. value must be a complete array or object.
+func children(value []json.Token) (keys []string, values [][]json.Token) {
+	isObject := value[0] == json.Delim('{')
+	pos := 1
+	for pos < len(value)-1 {
+		if isObject {
+			keys = append(keys, value[pos].(string))
+			pos++
+		}
+		end, _ := valueEnd(value, pos)
+		values = append(values, value[pos:end])
+		pos = end
+	}
+	return keys, values
+}
+
+// valueEnd returns the position next to the JSON value starting at tokens[pos].
+// ok is false if the value is incomplete.
+func valueEnd(tokens []json.Token, pos int) (end int, ok bool) {
+	depth := 0
+	for ; pos < len(tokens); pos++ {
+		if d, isDelim := tokens[pos].(json.Delim); isDelim {
+			if d == '[' || d == '{' {
+				depth++
+			} else {
+				depth--
+			}
+		}
+		if depth == 0 {
+			return pos + 1, true
+		}
+	}
+	return pos, false
+}
+
+// encodeTokens writes a complete JSON value represented by tokens in the compact form.
+func encodeTokens(buffer *bytes.Buffer, tokens []json.Token) {
+	type frame struct {
+		isObject bool
+		first    bool
+		afterKey bool
+	}
+	var stack []frame
+
+	for _, token := range tokens {
+		isEnd := token == json.Delim(']') || token == json.Delim('}')
+		if len(stack) > 0 && !isEnd {
+			top := &stack[len(stack)-1]
+			if top.isObject && !top.afterKey {
+				if !top.first {
+					buffer.WriteByte(',')
+				}
+				top.first = false
+				top.afterKey = true
+				encodeString(buffer, token.(string))
+				buffer.WriteByte(':')
+				continue
+			}
+			if top.isObject {
+				top.afterKey = false
+			} else {
+				if !top.first {
+					buffer.WriteByte(',')
+				}
+				top.first = false
+			}
+		}
+
+		switch v := token.(type) {
+		case json.Delim:
+			switch v {
+			case '[', '{':
+				stack = append(stack, frame{isObject: v == '{', first: true})
+			default:
+				stack = stack[:len(stack)-1]
+			}
+			buffer.WriteString(v.String())
+		case json.Number:
+			buffer.WriteString(v.String())
+		case string:
+			encodeString(buffer, v)
+		default:
+			b, _ := json.Marshal(v)
+			buffer.Write(b)
+		}
+	}
+}
+
+// encodeString writes s as a JSON string without escaping HTML characters.
+func encodeString(buffer *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	buffer.Truncate(buffer.Len() - 1) // Encode appends a newline
+}
+
+// pluralize returns a phrase like "1 item" or "3 items".
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
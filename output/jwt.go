@@ -0,0 +1,375 @@
+package output
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // for crypto.SHA256
+	_ "crypto/sha512" // for crypto.SHA384 and crypto.SHA512
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
+)
+
+// JWTKey is a key used to verify signatures of JWTs.
+type JWTKey struct {
+	secret    []byte           // HMAC secret
+	publicKey crypto.PublicKey // RSA, ECDSA or Ed25519 public key
+}
+
+// LoadJWTKey loads a PEM-encoded public key or certificate from path.
+// If the file is not PEM, its content without trailing newlines is used as
+// an HMAC secret.
+func LoadJWTKey(path string) (*JWTKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading JWT key '%s'", path)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return &JWTKey{secret: bytes.TrimRight(data, "\r\n")}, nil
+	}
+
+	var publicKey crypto.PublicKey
+	switch block.Type {
+	case "PUBLIC KEY":
+		publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		publicKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			publicKey = cert.PublicKey
+		}
+	default:
+		return nil, errors.Errorf("unsupported PEM block in '%s': %s", path, block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing JWT key '%s'", path)
+	}
+	return &JWTKey{publicKey: publicKey}, nil
+}
+
+// verify checks signature of signingInput signed with alg.
+func (k *JWTKey) verify(alg string, signingInput, signature []byte) error {
+	var hash crypto.Hash
+	switch {
+	case alg == "EdDSA":
+	case strings.HasSuffix(alg, "256"):
+		hash = crypto.SHA256
+	case strings.HasSuffix(alg, "384"):
+		hash = crypto.SHA384
+	case strings.HasSuffix(alg, "512"):
+		hash = crypto.SHA512
+	default:
+		return errors.Errorf("unsupported algorithm: %s", alg)
+	}
+	var digest []byte
+	if hash != 0 {
+		h := hash.New()
+		h.Write(signingInput)
+		digest = h.Sum(nil)
+	}
+
+	invalid := errors.New("invalid signature")
+	switch alg[:2] {
+	case "HS":
+		if k.secret == nil {
+			return errors.Errorf("%s needs an HMAC secret", alg)
+		}
+		mac := hmac.New(hash.New, k.secret)
+		mac.Write(signingInput)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return invalid
+		}
+	case "RS", "PS":
+		publicKey, ok := k.publicKey.(*rsa.PublicKey)
+		if !ok {
+			return errors.Errorf("%s needs an RSA public key", alg)
+		}
+		if alg[0] == 'R' {
+			err := rsa.VerifyPKCS1v15(publicKey, hash, digest, signature)
+			if err != nil {
+				return invalid
+			}
+		} else {
+			err := rsa.VerifyPSS(publicKey, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+			if err != nil {
+				return invalid
+			}
+		}
+	case "ES":
+		publicKey, ok := k.publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.Errorf("%s needs an ECDSA public key", alg)
+		}
+		// The signature is the concatenation of R and S
+		half := len(signature) / 2
+		r := new(big.Int).SetBytes(signature[:half])
+		s := new(big.Int).SetBytes(signature[half:])
+		if len(signature) == 0 || !ecdsa.Verify(publicKey, digest, r, s) {
+			return invalid
+		}
+	case "Ed":
+		publicKey, ok := k.publicKey.(ed25519.PublicKey)
+		if !ok {
+			return errors.Errorf("%s needs an Ed25519 public key", alg)
+		}
+		if !ed25519.Verify(publicKey, signingInput, signature) {
+			return invalid
+		}
+	default:
+		return errors.Errorf("unsupported algorithm: %s", alg)
+	}
+	return nil
+}
+
+// jwt is a decoded JWS in the compact serialization.
+type jwt struct {
+	source       string // where the token is found (e.g. "Authorization header")
+	alg          string
+	header       []json.Token
+	claims       []json.Token
+	signingInput []byte
+	signature    []byte
+}
+
+// parseJWT decodes s if it looks like a JWT, i.e. three base64url-encoded
+// parts whose first two are JSON objects and the first one has "alg".
+func parseJWT(s string) (*jwt, bool) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+	decodeObject := func(part string) ([]json.Token, bool) {
+		data, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return nil, false
+		}
+		toks, err := newTokenBuffer(json.NewDecoder(bytes.NewReader(data)))
+		if err != nil || len(toks.tokens) == 0 || kindOfValue(toks.tokens) != "object" {
+			return nil, false
+		}
+		if end, ok := valueEnd(toks.tokens, 0); !ok || end != len(toks.tokens) {
+			return nil, false
+		}
+		return toks.tokens, true
+	}
+
+	header, ok := decodeObject(parts[0])
+	if !ok {
+		return nil, false
+	}
+	claims, ok := decodeObject(parts[1])
+	if !ok {
+		return nil, false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, false
+	}
+
+	token := &jwt{
+		header:       header,
+		claims:       claims,
+		signingInput: []byte(parts[0] + "." + parts[1]),
+		signature:    signature,
+	}
+	keys, values := children(header)
+	for i, key := range keys {
+		if key == "alg" && kindOfValue(values[i]) == "string" {
+			token.alg = values[i][0].(string)
+		}
+	}
+	if token.alg == "" {
+		return nil, false
+	}
+	return token, true
+}
+
+// findJWTs returns JWTs in the Authorization, Cookie and Set-Cookie headers,
+// and in string values of JSON bodies.
+func findJWTs(header http.Header, body []byte, contentType string) []*jwt {
+	var found []*jwt
+	add := func(s string, source string) {
+		if token, ok := parseJWT(s); ok {
+			token.source = source
+			found = append(found, token)
+		}
+	}
+
+	for _, name := range headerNames(header, true) {
+		for _, value := range header[name] {
+			switch name {
+			case "Authorization":
+				fields := strings.Fields(value)
+				if len(fields) == 2 && strings.EqualFold(fields[0], "Bearer") {
+					add(fields[1], "Authorization header")
+				}
+			case "Cookie":
+				for _, cookie := range (&http.Request{Header: http.Header{name: {value}}}).Cookies() {
+					add(cookie.Value, fmt.Sprintf("Cookie %q", cookie.Name))
+				}
+			case "Set-Cookie":
+				for _, cookie := range (&http.Response{Header: http.Header{name: {value}}}).Cookies() {
+					add(cookie.Value, fmt.Sprintf("Set-Cookie %q", cookie.Name))
+				}
+			}
+		}
+	}
+
+	if isJSON(contentType) {
+		toks, err := newTokenBuffer(json.NewDecoder(bytes.NewReader(body)))
+		if err != nil {
+			return found
+		}
+		for pos := 0; pos < len(toks.tokens); {
+			end, _ := valueEnd(toks.tokens, pos)
+			findJWTsInJSON(toks.tokens[pos:end], "$", func(s string, path string) {
+				add(s, "body at "+path)
+			})
+			pos = end
+		}
+	}
+	return found
+}
+
+// findJWTsInJSON calls add with each string in value and its JSONPath.
+func findJWTsInJSON(value []json.Token, path string, add func(s string, source string)) {
+	switch kindOfValue(value) {
+	case "string":
+		add(value[0].(string), path)
+	case "array":
+		_, values := children(value)
+		for i, v := range values {
+			findJWTsInJSON(v, fmt.Sprintf("%s[%d]", path, i), add)
+		}
+	case "object":
+		keys, values := children(value)
+		for i, v := range values {
+			findJWTsInJSON(v, path+"."+keys[i], add)
+		}
+	}
+}
+
+// jwtPrinter prints decoded JWTs.
+type jwtPrinter struct {
+	writer     io.Writer
+	palette    *HeaderPalette
+	colorize   func(arg interface{}, color Color) interface{}
+	valueColor func(kind string) Color
+	key        *JWTKey // signatures are not verified if nil
+	now        time.Time
+}
+
+// printJWTs prints JWTs found in header and body. JSON bodies are read to find
+// JWTs; the returned reader must be used to read body afterwards. body may be
+// nil if it should not be examined.
+func (j *jwtPrinter) printJWTs(header http.Header, body io.Reader, contentType string) (io.Reader, error) {
+	var content []byte
+	if body != nil && isJSON(contentType) {
+		var err error
+		content, err = ioutil.ReadAll(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading body")
+		}
+		body = bytes.NewReader(content)
+	}
+
+	for _, token := range findJWTs(header, content, contentType) {
+		j.printJWT(token)
+	}
+	return body, nil
+}
+
+func (j *jwtPrinter) printJWT(token *jwt) {
+	status := j.colorize("signature not verified", j.palette.FieldValue)
+	switch {
+	case token.alg == "none":
+		status = j.colorize("unsigned", j.palette.NonSuccessfulStatus)
+	case j.key != nil:
+		if err := j.key.verify(token.alg, token.signingInput, token.signature); err != nil {
+			status = j.colorize("signature verification failed: "+err.Error(), j.palette.NonSuccessfulStatus)
+		} else {
+			status = j.colorize("signature verified", j.palette.SuccessfulStatus)
+		}
+	}
+	fmt.Fprintf(j.writer, "%s %s%s%s\n",
+		j.colorize("JWT in "+token.source, j.palette.FieldName),
+		j.colorize("(", j.palette.FieldSeparator),
+		status,
+		j.colorize(")", j.palette.FieldSeparator))
+
+	fmt.Fprintf(j.writer, "  %s\n", j.colorize("Header:", j.palette.FieldName))
+	j.printMembers(token.header)
+	fmt.Fprintf(j.writer, "  %s\n", j.colorize("Claims:", j.palette.FieldName))
+	warnings := j.printMembers(token.claims)
+	for _, warning := range warnings {
+		fmt.Fprintf(j.writer, "  %s\n", j.colorize("WARNING: "+warning, j.palette.NonSuccessfulStatus))
+	}
+	fmt.Fprintln(j.writer)
+}
+
+// printMembers prints the members of a JSON object aligned in columns.
+// Timestamps (exp, iat and nbf) are followed by human-readable dates.
+// It returns warnings about the validity period.
+func (j *jwtPrinter) printMembers(object []json.Token) []string {
+	keys, values := children(object)
+	width := 0
+	for _, key := range keys {
+		if w := runewidth.StringWidth(key); w > width {
+			width = w
+		}
+	}
+
+	var warnings []string
+	for i, key := range keys {
+		cell := newTableCell(values[i])
+		text := fmt.Sprint(j.colorize(cell.text, j.valueColor(cell.kind)))
+		if t, ok := jwtTime(key, values[i]); ok {
+			date := t.UTC().Format("2006-01-02 15:04:05 UTC")
+			text += " " + fmt.Sprint(j.colorize("("+date+")", j.palette.FieldSeparator))
+			if key == "exp" && !j.now.Before(t) {
+				warnings = append(warnings, "expired at "+date)
+			}
+			if key == "nbf" && j.now.Before(t) {
+				warnings = append(warnings, "not valid before "+date)
+			}
+		}
+		fmt.Fprintf(j.writer, "    %s%s %s%s\n",
+			j.colorize(key, j.palette.FieldName),
+			j.colorize(":", j.palette.FieldSeparator),
+			strings.Repeat(" ", width-runewidth.StringWidth(key)),
+			text)
+	}
+	return warnings
+}
+
+// jwtTime returns the time of a NumericDate claim (seconds since the epoch).
+func jwtTime(key string, value []json.Token) (time.Time, bool) {
+	if (key != "exp" && key != "iat" && key != "nbf") || kindOfValue(value) != "number" {
+		return time.Time{}, false
+	}
+	seconds, err := value[0].(json.Number).Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	whole := math.Floor(seconds)
+	return time.Unix(int64(whole), int64((seconds-whole)*float64(time.Second))), true
+}
@@ -0,0 +1,235 @@
+package output
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+// encodeJWT returns a JWT whose signature is computed by sign.
+func encodeJWT(header, claims string, sign func(signingInput []byte) []byte) string {
+	signingInput := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claims))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signingInput)))
+}
+
+func signHS256(secret string) func([]byte) []byte {
+	return func(signingInput []byte) []byte {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(signingInput)
+		return mac.Sum(nil)
+	}
+}
+
+func writeTempFile(t *testing.T, data []byte) string {
+	file, err := ioutil.TempFile("", "jwt-key")
+	if err != nil {
+		t.Fatalf("failed to create temp file: err=%+v", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		t.Fatalf("failed to write temp file: err=%+v", err)
+	}
+	return file.Name()
+}
+
+func TestPlainPrinter_PrintJWTs(t *testing.T) {
+	secretFile := writeTempFile(t, []byte("secret\n"))
+	defer os.Remove(secretFile)
+	key, err := LoadJWTKey(secretFile)
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	expired := encodeJWT(`{"alg":"HS256","typ":"JWT"}`, `{"sub":"alice","iat":946684800,"exp":946688400.5}`, signHS256("secret"))
+	notYetValid := encodeJWT(`{"alg":"HS256"}`, `{"sub":"bob","nbf":4102444800}`, signHS256("secret"))
+	wrongSecret := encodeJWT(`{"alg":"HS256"}`, `{"sub":"carol"}`, signHS256("wrong"))
+
+	testCases := []struct {
+		title       string
+		header      http.Header
+		body        string
+		contentType string
+		key         *JWTKey
+		expected    string
+	}{
+		{
+			title:  "Authorization header",
+			header: http.Header{"Authorization": {"Bearer " + expired}},
+			expected: strings.Join([]string{
+				"JWT in Authorization header (signature not verified)",
+				"  Header:",
+				"    alg: HS256",
+				"    typ: JWT",
+				"  Claims:",
+				"    sub: alice",
+				"    iat: 946684800 (2000-01-01 00:00:00 UTC)",
+				"    exp: 946688400.5 (2000-01-01 01:00:00 UTC)",
+				"  WARNING: expired at 2000-01-01 01:00:00 UTC",
+				"",
+				"",
+			}, "\n"),
+		},
+		{
+			title:  "Set-Cookie header",
+			header: http.Header{"Set-Cookie": {"session=" + notYetValid + "; Path=/; HttpOnly"}},
+			key:    key,
+			expected: strings.Join([]string{
+				`JWT in Set-Cookie "session" (signature verified)`,
+				"  Header:",
+				"    alg: HS256",
+				"  Claims:",
+				"    sub: bob",
+				"    nbf: 4102444800 (2100-01-01 00:00:00 UTC)",
+				"  WARNING: not valid before 2100-01-01 00:00:00 UTC",
+				"",
+				"",
+			}, "\n"),
+		},
+		{
+			title:       "JSON body",
+			header:      http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}},
+			body:        `{"token_type": "Bearer", "tokens": [{"access_token": "` + wrongSecret + `"}]}`,
+			contentType: "application/json",
+			key:         key,
+			expected: strings.Join([]string{
+				"JWT in body at $.tokens[0].access_token (signature verification failed: invalid signature)",
+				"  Header:",
+				"    alg: HS256",
+				"  Claims:",
+				"    sub: carol",
+				"",
+				"",
+			}, "\n"),
+		},
+		{
+			title:       "Not JSON",
+			body:        wrongSecret,
+			contentType: "text/plain",
+			expected:    "",
+		},
+		{
+			title:  "Not JWT",
+			header: http.Header{"Authorization": {"Bearer abc.def.ghi"}},
+			body:   `{"version": "1.2.3"}`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var buffer strings.Builder
+			printer := NewPlainPrinterWithConfig(PlainPrinterConfig{Writer: &buffer, JWTKey: tt.key})
+
+			// Exercise
+			body, err := PrintJWTs(printer, tt.header, strings.NewReader(tt.body), tt.contentType)
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s", tt.expected, buffer.String())
+			}
+			rest, _ := ioutil.ReadAll(body)
+			if string(rest) != tt.body {
+				t.Errorf("body must be readable after PrintJWTs: expected=%q, actual=%q", tt.body, string(rest))
+			}
+		})
+	}
+}
+
+func TestJWTKey_Verify_PublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: err=%+v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: err=%+v", err)
+	}
+	signRS256 := func(signingInput []byte) []byte {
+		digest := sha256.Sum256(signingInput)
+		signature, _ := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+		return signature
+	}
+	signES256 := func(signingInput []byte) []byte {
+		digest := sha256.Sum256(signingInput)
+		r, s, _ := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+		signature := make([]byte, 64)
+		rb, sb := r.Bytes(), s.Bytes()
+		copy(signature[32-len(rb):32], rb)
+		copy(signature[64-len(sb):], sb)
+		return signature
+	}
+	loadKey := func(publicKey interface{}) *JWTKey {
+		der, err := x509.MarshalPKIXPublicKey(publicKey)
+		if err != nil {
+			t.Fatalf("failed to marshal public key: err=%+v", err)
+		}
+		path := writeTempFile(t, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		defer os.Remove(path)
+		key, err := LoadJWTKey(path)
+		if err != nil {
+			t.Fatalf("unexpected error: err=%+v", err)
+		}
+		return key
+	}
+	rsaPublicKey := loadKey(&rsaKey.PublicKey)
+	ecPublicKey := loadKey(&ecKey.PublicKey)
+
+	testCases := []struct {
+		title string
+		token string
+		key   *JWTKey
+		valid bool
+	}{
+		{
+			title: "RS256",
+			token: encodeJWT(`{"alg":"RS256"}`, `{}`, signRS256),
+			key:   rsaPublicKey,
+			valid: true,
+		},
+		{
+			title: "ES256",
+			token: encodeJWT(`{"alg":"ES256"}`, `{}`, signES256),
+			key:   ecPublicKey,
+			valid: true,
+		},
+		{
+			title: "Wrong key type",
+			token: encodeJWT(`{"alg":"RS256"}`, `{}`, signRS256),
+			key:   ecPublicKey,
+			valid: false,
+		},
+		{
+			title: "Tampered",
+			token: encodeJWT(`{"alg":"ES256"}`, `{}`, signES256) + "AA",
+			key:   ecPublicKey,
+			valid: false,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			token, ok := parseJWT(tt.token)
+			if !ok {
+				t.Fatalf("failed to parse JWT: %s", tt.token)
+			}
+			err := tt.key.verify(token.alg, token.signingInput, token.signature)
+			if (err == nil) != tt.valid {
+				t.Errorf("unexpected result of verify: valid=%v, err=%v", tt.valid, err)
+			}
+		})
+	}
+}
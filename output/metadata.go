@@ -0,0 +1,114 @@
+package output
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/mattn/go-runewidth"
+)
+
+// Metadata is information about an exchange printed after the response.
+type Metadata struct {
+	Elapsed      time.Duration // from sending the request until reading the whole response
+	BodySize     int64         // bytes of the response body after Content-Encoding is decoded
+	Decompressed bool          // whether the body was transparently decompressed from gzip
+	Proto        string        // e.g. "HTTP/1.1"
+	RemoteAddr   string        // empty if unknown
+	TLSVersion   uint16        // 0 if TLS is not used
+}
+
+// fields returns the name and the value of each line of metadata.
+func (m *Metadata) fields() [][2]string {
+	bodySize := formatBytes(m.BodySize)
+	if m.Decompressed {
+		bodySize += ", decompressed from gzip"
+	}
+	fields := [][2]string{
+		{"Elapsed time", formatElapsed(m.Elapsed)},
+		{"Body size", bodySize},
+		{"Protocol", m.Proto},
+	}
+	if m.RemoteAddr != "" {
+		fields = append(fields, [2]string{"Remote address", m.RemoteAddr})
+	}
+	if m.TLSVersion != 0 {
+		fields = append(fields, [2]string{"TLS version", tlsVersionName(m.TLSVersion)})
+	}
+	return fields
+}
+
+// formatElapsed rounds d for readability (e.g. "123.46ms" or "1.235s").
+func formatElapsed(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.String()
+	}
+}
+
+// formatBytes returns a size like "1.2K (1234 bytes)".
+func formatBytes(n int64) string {
+	if n < 1024 {
+		return pluralize(int(n), "byte")
+	}
+	return fmt.Sprintf("%s (%s)", bytefmt.ByteSize(uint64(n)), pluralize(int(n), "byte"))
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionSSL30:
+		return "SSL 3.0"
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return fmt.Sprintf("0x%04x", version)
+	}
+}
+
+// writeMetadata writes the fields of meta aligned in columns.
+func writeMetadata(
+	w io.Writer,
+	meta *Metadata,
+	palette *HeaderPalette,
+	colorize func(arg interface{}, color Color) interface{},
+) {
+	writeFields(w, "", meta.fields(), palette, colorize)
+}
+
+// writeFields writes pairs of a name and a value as "name: value" lines
+// with values aligned. Each line is prefixed with indent.
+func writeFields(
+	w io.Writer,
+	indent string,
+	fields [][2]string,
+	palette *HeaderPalette,
+	colorize func(arg interface{}, color Color) interface{},
+) {
+	width := 0
+	for _, field := range fields {
+		if n := runewidth.StringWidth(field[0]); n > width {
+			width = n
+		}
+	}
+	for _, field := range fields {
+		fmt.Fprintf(w, "%s%s%s %s%s\n",
+			indent,
+			colorize(field[0], palette.FieldName),
+			colorize(":", palette.FieldSeparator),
+			strings.Repeat(" ", width-runewidth.StringWidth(field[0])),
+			colorize(field[1], palette.FieldValue))
+	}
+}
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"crypto/tls"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlainPrinter_PrintMetadata(t *testing.T) {
+	testCases := []struct {
+		title    string
+		meta     Metadata
+		expected string
+	}{
+		{
+			title: "TLS",
+			meta: Metadata{
+				Elapsed:      123456789 * time.Nanosecond,
+				BodySize:     1536,
+				Decompressed: true,
+				Proto:        "HTTP/2.0",
+				RemoteAddr:   "93.184.216.34:443",
+				TLSVersion:   tls.VersionTLS13,
+			},
+			expected: strings.Join([]string{
+				"Elapsed time:   123.46ms",
+				"Body size:      1.5K (1536 bytes), decompressed from gzip",
+				"Protocol:       HTTP/2.0",
+				"Remote address: 93.184.216.34:443",
+				"TLS version:    TLS 1.3",
+				"",
+			}, "\n"),
+		},
+		{
+			title: "Plain HTTP",
+			meta: Metadata{
+				Elapsed:  2345678901 * time.Nanosecond,
+				BodySize: 1,
+				Proto:    "HTTP/1.1",
+			},
+			expected: strings.Join([]string{
+				"Elapsed time: 2.346s",
+				"Body size:    1 byte",
+				"Protocol:     HTTP/1.1",
+				"",
+			}, "\n"),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			var buffer strings.Builder
+			printer := NewPlainPrinter(&buffer)
+
+			if err := PrintMetadata(printer, &tt.meta); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s", tt.expected, buffer.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// bodyPart is a part of a multipart body.
+type bodyPart struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// readMultipart reads all parts of body if contentType is multipart/*.
+// It returns false if body is not multipart; the returned reader must be used
+// to read body in that case. Malformed multipart bodies are regarded as not
+// multipart.
+func readMultipart(body io.Reader, contentType string) (io.Reader, string, []bodyPart, bool, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return body, "", nil, false, nil
+	}
+	boundary := params["boundary"]
+
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, "", nil, false, errors.Wrap(err, "reading body")
+	}
+	original := bytes.NewReader(content)
+
+	var parts []bodyPart
+	reader := multipart.NewReader(bytes.NewReader(content), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return original, "", nil, false, nil
+		}
+		data, err := ioutil.ReadAll(part)
+		if err != nil {
+			return original, "", nil, false, nil
+		}
+		parts = append(parts, bodyPart{header: part.Header, body: data})
+	}
+	return nil, boundary, parts, true, nil
+}
+
+// partContentType returns Content-Type of part. The default is text/plain (RFC 2046).
+func partContentType(part bodyPart) string {
+	if contentType := part.header.Get("Content-Type"); contentType != "" {
+		return contentType
+	}
+	return "text/plain"
+}
+
+// newlineTracker remembers whether the last byte written is a newline.
+type newlineTracker struct {
+	writer  io.Writer
+	written bool
+	newline bool
+}
+
+func (t *newlineTracker) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		t.written = true
+		t.newline = p[len(p)-1] == '\n'
+	}
+	return t.writer.Write(p)
+}
+
+// needsNewline reports whether something was written without a trailing newline.
+func (t *newlineTracker) needsNewline() bool {
+	return t.written && !t.newline
+}
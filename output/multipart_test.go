@@ -0,0 +1,116 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrettyPrinter_PrintBody_Multipart(t *testing.T) {
+	testCases := []struct {
+		title       string
+		contentType string
+		body        string
+		expected    string
+	}{
+		{
+			title:       "multipart/mixed",
+			contentType: `multipart/mixed; boundary="xyz"`,
+			body: strings.Join([]string{
+				"--xyz",
+				"Content-Type: application/json",
+				"",
+				`{"id": 1}`,
+				"--xyz",
+				"Content-Type: text/plain",
+				"",
+				"hello",
+				"--xyz",
+				"",
+				"no header",
+				"--xyz",
+				"Content-Type: application/octet-stream",
+				"",
+				"\x00\x01\x02",
+				"--xyz--",
+				"",
+			}, "\r\n"),
+			expected: strings.Join([]string{
+				"--xyz",
+				"Content-Type: application/json",
+				"",
+				"{",
+				`    "id": 1`,
+				"}",
+				"--xyz",
+				"Content-Type: text/plain",
+				"",
+				"hello",
+				"--xyz",
+				"",
+				"no header",
+				"--xyz",
+				"Content-Type: application/octet-stream",
+				"",
+				strings.TrimSuffix(binaryNotice, "\n"),
+				"--xyz--",
+				"",
+			}, "\n"),
+		},
+		{
+			title:       "multipart/byteranges",
+			contentType: "multipart/byteranges; boundary=b",
+			body: strings.Join([]string{
+				"--b",
+				"Content-Range: bytes 0-4/100",
+				"Content-Type: text/plain",
+				"",
+				"01234",
+				"--b",
+				"Content-Range: bytes 10-11/100",
+				"Content-Type: text/plain",
+				"",
+				"",
+				"--b--",
+			}, "\r\n"),
+			expected: strings.Join([]string{
+				"--b",
+				"Content-Range: bytes 0-4/100",
+				"Content-Type: text/plain",
+				"",
+				"01234",
+				"--b",
+				"Content-Range: bytes 10-11/100",
+				"Content-Type: text/plain",
+				"",
+				"--b--",
+				"",
+			}, "\n"),
+		},
+		{
+			title:       "Malformed",
+			contentType: "multipart/mixed; boundary=b",
+			body:        "--b\r\nContent-Type: text/plain\r\n\r\nunterminated",
+			expected:    "--b\r\nContent-Type: text/plain\r\n\r\nunterminated",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var buffer strings.Builder
+			printer := NewPrettyPrinter(PrettyPrinterConfig{
+				Writer: &buffer,
+				Binary: BinaryNotice,
+			})
+
+			// Exercise
+			if err := printer.PrintBody(strings.NewReader(tt.body), tt.contentType); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s", tt.expected, buffer.String())
+			}
+		})
+	}
+}
@@ -5,11 +5,86 @@ type Options struct {
 	PrintRequestBody    bool
 	PrintResponseHeader bool
 	PrintResponseBody   bool
+	PrintMeta           bool // elapsed time, body size and connection information
+	PrintTiming         bool // breakdown of the time into DNS lookup, TCP connect, etc.
+	PrintTLS            bool // TLS session and the certificate chain of the server
+	// Print intermediate requests and responses of redirects as specified by History.
+	PrintAll bool
+	History  HistoryOptions
 
 	EnableFormat bool
 	EnableColor  bool
+	ColorDepth   ColorDepth
+	Hyperlinks   bool           // emit OSC 8 hyperlinks for URLs in headers
+	Theme        *Theme         // the default theme is used if nil
+	Format       *FormatOptions // DefaultFormatOptions is used if nil
+	Filter       *Filter        // applied to response bodies if not nil
+	Limits       JSONLimits
+	Binary       BinaryMode
+	ProtoSchema  *ProtoSchema // protobuf bodies are decoded without schema if nil
+	View         View
+	Columns      []string
+	// Charset of response bodies. If empty, the charset in Content-Type is used.
+	ResponseCharset string
+	// Print a summary of Problem Details to stderr when --check-status fails.
+	ProblemSummary bool
+	// Print JWTs found in headers and JSON bodies beneath the header block.
+	DecodeJWT bool
+	JWTKey    *JWTKey // signatures of JWTs are not verified if nil
+	// Explain well-known header fields such as Date and Cache-Control.
+	AnnotateHeaders bool
+	HeaderFilter    *HeaderFilter // all header fields are printed if nil
+	// Warn about certificates expiring within this number of days. 0 disables warnings.
+	CertWarningDays int
+	// Width and height of the terminal. 0 means unknown (e.g. stdout is not a terminal).
+	TerminalWidth  int
+	TerminalHeight int
+	// Pager command that output not fitting in the terminal is piped through.
+	// Empty means no pager.
+	Pager       string
+	PagerAlways bool // use the pager even if output fits in the terminal
 
 	Download   bool
 	OutputFile string
 	Overwrite  bool
+	Continue   bool // resume a partial download of OutputFile
 }
+
+// HistoryOptions controls what is printed for intermediate requests and
+// responses of redirects (corresponds to httpie's --history-print).
+type HistoryOptions struct {
+	PrintRequestHeader  bool
+	PrintRequestBody    bool
+	PrintResponseHeader bool
+	PrintResponseBody   bool
+}
+
+// FormatOptions controls the details of formatting (corresponds to httpie's --format-options).
+type FormatOptions struct {
+	JSONIndent   int
+	JSONSortKeys bool
+	HeadersSort  bool
+}
+
+// DefaultFormatOptions is the formatting used when --format-options is not specified.
+var DefaultFormatOptions = FormatOptions{
+	JSONIndent:   4,
+	JSONSortKeys: false,
+	HeadersSort:  true,
+}
+
+// JSONLimits restricts how much of large JSON values is printed. 0 means unlimited.
+type JSONLimits struct {
+	MaxDepth        int // arrays and objects nested deeper than this are collapsed
+	MaxArrayItems   int // items after this are elided
+	MaxStringLength int // characters after this are elided
+}
+
+// ColorDepth is the number of colors that the terminal supports.
+type ColorDepth int
+
+const (
+	ColorDepth8 ColorDepth = iota
+	ColorDepth256
+	ColorDepthTrue
+)
@@ -0,0 +1,196 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
+)
+
+// DefaultPager is the pager used if $PAGER is not set.
+const DefaultPager = "less -FRX"
+
+// PagerWriter pipes output through a pager once it does not fit in the
+// terminal. Output is buffered until then, and written as it is if it fits.
+// Close must be called to flush the output and wait for the pager.
+type PagerWriter struct {
+	out     io.Writer
+	command string
+	always  bool // use the pager even if output fits in the terminal
+	width   int
+	height  int
+
+	buffer    bytes.Buffer
+	rows      int // rows occupied by the complete lines in buffer
+	lineStart int // position of the incomplete line in buffer
+
+	pager     *exec.Cmd
+	stdin     io.WriteCloser
+	interrupt chan os.Signal // SIGINT is caught while the pager runs
+	direct    bool           // write to out without the pager
+	err       error          // error in starting the pager, which is returned by Close
+}
+
+// NewPagerWriter creates a PagerWriter that writes to out through the pager
+// command in options.
+func NewPagerWriter(out io.Writer, options *Options) *PagerWriter {
+	return &PagerWriter{
+		out:     out,
+		command: options.Pager,
+		always:  options.PagerAlways,
+		width:   options.TerminalWidth,
+		height:  options.TerminalHeight,
+		// The height is needed to know whether output fits in the terminal
+		direct: options.Pager == "" || (options.TerminalHeight <= 0 && !options.PagerAlways),
+	}
+}
+
+func (w *PagerWriter) Write(p []byte) (int, error) {
+	if w.direct {
+		return w.out.Write(p)
+	}
+	if w.stdin != nil {
+		if _, err := w.stdin.Write(p); err != nil {
+			// The pager has quit; discard the rest
+			w.stdin.Close()
+			w.stdin = nil
+			w.stopInterrupt()
+			w.direct = true
+			w.out = ioutil.Discard
+		}
+		return len(p), nil
+	}
+
+	w.buffer.Write(p)
+	if w.always || w.overflows() {
+		w.startPager()
+	}
+	return len(p), nil
+}
+
+// overflows counts rows of new complete lines in the buffer and reports
+// whether they exceed the terminal.
+func (w *PagerWriter) overflows() bool {
+	content := w.buffer.Bytes()
+	for {
+		i := bytes.IndexByte(content[w.lineStart:], '\n')
+		if i < 0 {
+			break
+		}
+		w.rows += displayRows(content[w.lineStart:w.lineStart+i], w.width)
+		w.lineStart += i + 1
+	}
+	// Keep the last row for the shell prompt
+	return w.rows >= w.height
+}
+
+// displayRows returns the number of terminal rows that line occupies.
+// Escape sequences for colors are not counted in the width.
+func displayRows(line []byte, terminalWidth int) int {
+	if terminalWidth <= 0 {
+		return 1
+	}
+	width := 0
+	for len(line) > 0 {
+		if line[0] == 0x1b {
+			// Skip CSI sequences such as "\x1b[31m"
+			end := 1
+			if len(line) > 1 && line[1] == '[' {
+				end = 2
+				for end < len(line) && !(0x40 <= line[end] && line[end] <= 0x7e) {
+					end++
+				}
+				end++
+			}
+			if end > len(line) {
+				end = len(line)
+			}
+			line = line[end:]
+			continue
+		}
+		r, size := utf8.DecodeRune(line)
+		width += runewidth.RuneWidth(r)
+		line = line[size:]
+	}
+	if width == 0 {
+		return 1
+	}
+	return (width + terminalWidth - 1) / terminalWidth
+}
+
+// startPager starts the pager and writes the buffered output to it. Output
+// is written directly if the pager cannot be started, and the error is
+// returned by Close.
+func (w *PagerWriter) startPager() {
+	defer w.buffer.Reset()
+
+	cmd := pagerCommand(w.command)
+	var stdin io.WriteCloser
+	err := errors.New("empty pager command")
+	if cmd != nil {
+		cmd.Stdout = w.out
+		cmd.Stderr = os.Stderr
+		stdin, err = cmd.StdinPipe()
+		if err == nil {
+			err = cmd.Start()
+		}
+	}
+	if err != nil {
+		w.direct = true
+		w.err = errors.Wrapf(err, "starting pager '%s'", w.command)
+		w.out.Write(w.buffer.Bytes())
+		return
+	}
+	w.pager = cmd
+	w.stdin = stdin
+	// Ctrl-C is sent to the pager as well, which handles it (e.g. less stops
+	// following the output). Quitting here would leave the pager orphaned.
+	w.interrupt = make(chan os.Signal, 1)
+	signal.Notify(w.interrupt, os.Interrupt)
+	w.Write(w.buffer.Bytes())
+}
+
+// stopInterrupt restores the default behavior of SIGINT.
+func (w *PagerWriter) stopInterrupt() {
+	if w.interrupt != nil {
+		signal.Stop(w.interrupt)
+		w.interrupt = nil
+	}
+}
+
+// Close writes the buffered output and waits for the pager to quit. It returns
+// an error if the pager cannot be started or exits with an error.
+func (w *PagerWriter) Close() error {
+	if !w.direct && w.pager == nil && w.lineStart < w.buffer.Len() {
+		// The last line without a newline may not fit
+		if w.rows+displayRows(w.buffer.Bytes()[w.lineStart:], w.width) >= w.height {
+			w.startPager()
+		}
+	}
+	if w.pager == nil {
+		if w.buffer.Len() > 0 {
+			_, err := w.out.Write(w.buffer.Bytes())
+			w.buffer.Reset()
+			if err != nil {
+				return err
+			}
+		}
+		return w.err
+	}
+	if w.stdin != nil {
+		w.stdin.Close()
+	}
+	err := w.pager.Wait()
+	w.pager = nil
+	w.stopInterrupt()
+	if err != nil {
+		return errors.Wrapf(err, "running pager '%s'", w.command)
+	}
+	return nil
+}
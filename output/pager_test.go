@@ -0,0 +1,99 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPagerWriter(t *testing.T) {
+	testCases := []struct {
+		title         string
+		options       Options
+		writes        []string
+		expected      string
+		shouldBeError bool
+	}{
+		{
+			title:    "Fits in the terminal",
+			options:  Options{Pager: "tr a-z A-Z", TerminalWidth: 80, TerminalHeight: 3},
+			writes:   []string{"foo\n", "bar\n"},
+			expected: "foo\nbar\n",
+		},
+		{
+			title:    "Too many lines",
+			options:  Options{Pager: "tr a-z A-Z", TerminalWidth: 80, TerminalHeight: 3},
+			writes:   []string{"foo\n", "bar\n", "baz", "\nqux\n"},
+			expected: "FOO\nBAR\nBAZ\nQUX\n",
+		},
+		{
+			title:    "Wrapped line",
+			options:  Options{Pager: "tr a-z A-Z", TerminalWidth: 4, TerminalHeight: 3},
+			writes:   []string{"foo\n", "bar\n", "quxquux"},
+			expected: "FOO\nBAR\nQUXQUUX",
+		},
+		{
+			title:    "Colors are not counted",
+			options:  Options{Pager: "tr a-z A-Z", TerminalWidth: 3, TerminalHeight: 3},
+			writes:   []string{"\x1b[1;31mfoo\x1b[0m\n", "bar\n"},
+			expected: "\x1b[1;31mfoo\x1b[0m\nbar\n",
+		},
+		{
+			title:    "Always",
+			options:  Options{Pager: "tr a-z A-Z", PagerAlways: true},
+			writes:   []string{"foo\n"},
+			expected: "FOO\n",
+		},
+		{
+			title:    "Quoted arguments",
+			options:  Options{Pager: `sed "s/foo bar/qux/"`, PagerAlways: true},
+			writes:   []string{"foo bar\n"},
+			expected: "qux\n",
+		},
+		{
+			title:    "Unknown height",
+			options:  Options{Pager: "tr a-z A-Z"},
+			writes:   []string{"foo\n", "bar\n", "baz\n"},
+			expected: "foo\nbar\nbaz\n",
+		},
+		{
+			title:         "Missing pager",
+			options:       Options{Pager: "no-such-pager-command", TerminalHeight: 1},
+			writes:        []string{"foo\n", "bar\n"},
+			expected:      "foo\nbar\n",
+			shouldBeError: true,
+		},
+		{
+			title:         "Failing pager",
+			options:       Options{Pager: "false", PagerAlways: true},
+			writes:        []string{"foo\n"},
+			expected:      "",
+			shouldBeError: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var buffer strings.Builder
+			writer := NewPagerWriter(&buffer, &tt.options)
+
+			// Exercise
+			for _, s := range tt.writes {
+				if _, err := writer.Write([]byte(s)); err != nil {
+					t.Fatalf("unexpected error: err=%+v", err)
+				}
+			}
+			err := writer.Close()
+			if tt.shouldBeError && err == nil {
+				t.Errorf("error expected but got nil")
+			}
+			if !tt.shouldBeError && err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=%q, actual=%q", tt.expected, buffer.String())
+			}
+		})
+	}
+}
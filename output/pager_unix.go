@@ -0,0 +1,25 @@
+// +build !windows
+
+package output
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// shellMetacharacters are the characters for which git runs its pager by the shell.
+const shellMetacharacters = "|&;<>()$`\\\"'*?[#~=%"
+
+// pagerCommand returns the command that runs pager. Like git, pager is run by
+// the shell if it needs to be (e.g. it contains quotes), and executed
+// directly otherwise.
+func pagerCommand(pager string) *exec.Cmd {
+	if strings.ContainsAny(pager, shellMetacharacters) {
+		return exec.Command("sh", "-c", pager)
+	}
+	args := strings.Fields(pager)
+	if len(args) == 0 {
+		return nil
+	}
+	return exec.Command(args[0], args[1:]...)
+}
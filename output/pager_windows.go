@@ -0,0 +1,18 @@
+// +build windows
+
+package output
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// pagerCommand returns the command that runs pager. pager is split by spaces
+// since there is no sh on Windows.
+func pagerCommand(pager string) *exec.Cmd {
+	args := strings.Fields(pager)
+	if len(args) == 0 {
+		return nil
+	}
+	return exec.Command(args[0], args[1:]...)
+}
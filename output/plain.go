@@ -1,21 +1,59 @@
 package output
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"code.cloudfoundry.org/bytefmt"
 	"github.com/pkg/errors"
 )
 
 type PlainPrinter struct {
-	writer io.Writer
+	writer       io.Writer
+	format       *FormatOptions
+	binary       BinaryMode
+	jwtKey       *JWTKey
+	annotate     bool
+	headerFilter *HeaderFilter
+	certWarning  int
+}
+
+type PlainPrinterConfig struct {
+	Writer   io.Writer
+	Format   *FormatOptions // DefaultFormatOptions is used if nil
+	Binary   BinaryMode
+	JWTKey   *JWTKey // signatures of JWTs are not verified if nil
+	Annotate bool    // explain well-known header fields
+	// Header fields not matching HeaderFilter are not printed. All fields are printed if nil.
+	HeaderFilter *HeaderFilter
+	// Certificates expiring within this number of days are warned about. 0 disables warnings.
+	CertWarningDays int
 }
 
 func NewPlainPrinter(writer io.Writer) Printer {
+	return newPlainPrinter(PlainPrinterConfig{Writer: writer})
+}
+
+func NewPlainPrinterWithConfig(config PlainPrinterConfig) Printer {
+	return newPlainPrinter(config)
+}
+
+func newPlainPrinter(config PlainPrinterConfig) *PlainPrinter {
+	format := config.Format
+	if format == nil {
+		format = &DefaultFormatOptions
+	}
 	return &PlainPrinter{
-		writer: writer,
+		writer:       config.Writer,
+		format:       format,
+		binary:       config.Binary,
+		jwtKey:       config.JWTKey,
+		annotate:     config.Annotate,
+		headerFilter: config.HeaderFilter,
+		certWarning:  config.CertWarningDays,
 	}
 }
 
@@ -30,8 +68,17 @@ func (p *PlainPrinter) PrintRequestLine(req *http.Request) error {
 }
 
 func (p *PlainPrinter) PrintHeader(header http.Header) error {
-	for name, values := range header {
-		for _, value := range values {
+	now := time.Now()
+	for _, name := range headerNames(header, p.format.HeadersSort) {
+		if !p.headerFilter.Match(name) {
+			continue
+		}
+		for _, value := range header[name] {
+			if p.annotate {
+				writeAnnotatedField(p.writer, name, value, value, now, &defaultHeaderPalette,
+					func(arg interface{}, color Color) interface{} { return arg })
+				continue
+			}
 			fmt.Fprintf(p.writer, "%s: %s\n", name, value)
 		}
 	}
@@ -39,7 +86,47 @@ func (p *PlainPrinter) PrintHeader(header http.Header) error {
 	return nil
 }
 
+func (p *PlainPrinter) PrintJWTs(header http.Header, body io.Reader, contentType string) (io.Reader, error) {
+	j := &jwtPrinter{
+		writer:     p.writer,
+		palette:    &defaultHeaderPalette,
+		colorize:   func(arg interface{}, color Color) interface{} { return arg },
+		valueColor: func(kind string) Color { return Color{} },
+		key:        p.jwtKey,
+		now:        time.Now(),
+	}
+	return j.printJWTs(header, body, contentType)
+}
+
+// PrintBody writes body as it is without converting its charset so that
+// redirected output stays byte-exact.
 func (p *PlainPrinter) PrintBody(body io.Reader, contentType string) error {
+	body, printed, err := printBinary(p.writer, body, contentType, p.binary, noColor)
+	if printed || err != nil {
+		return err
+	}
+	return p.writeBody(body)
+}
+
+func (p *PlainPrinter) PrintRequestBody(body io.Reader, contentType string) error {
+	form := &formPrinter{
+		writer:   p.writer,
+		palette:  &defaultHeaderPalette,
+		colorize: func(arg interface{}, color Color) interface{} { return arg },
+		printBody: func(w io.Writer, body io.Reader, contentType string) error {
+			_, err := io.Copy(w, body)
+			return err
+		},
+	}
+	body, printed, err := form.printForm(body, contentType)
+	if printed || err != nil {
+		return err
+	}
+	return p.PrintBody(body, contentType)
+}
+
+// writeBody writes body as it is.
+func (p *PlainPrinter) writeBody(body io.Reader) error {
 	_, err := io.Copy(p.writer, body)
 	if err != nil {
 		return errors.Wrap(err, "printing body")
@@ -47,7 +134,40 @@ func (p *PlainPrinter) PrintBody(body io.Reader, contentType string) error {
 	return nil
 }
 
+func (p *PlainPrinter) PrintMetadata(meta *Metadata) error {
+	writeMetadata(p.writer, meta, &defaultHeaderPalette,
+		func(arg interface{}, color Color) interface{} { return arg })
+	return nil
+}
+
+func (p *PlainPrinter) PrintTiming(timing *Timing) error {
+	return writeTimingJSON(p.writer, timing)
+}
+
+func (p *PlainPrinter) PrintTLS(state *tls.ConnectionState) error {
+	t := &tlsPrinter{
+		writer:      p.writer,
+		palette:     &defaultHeaderPalette,
+		colorize:    func(arg interface{}, color Color) interface{} { return arg },
+		warningDays: p.certWarning,
+		now:         time.Now(),
+	}
+	t.printTLS(state)
+	return nil
+}
+
 func (p *PlainPrinter) PrintDownload(length int64, filename string) error {
 	fmt.Fprintf(p.writer, "Downloading %sB to \"%s\"\n", bytefmt.ByteSize(uint64(length)), filename)
 	return nil
 }
+
+func (p *PlainPrinter) PrintResume(offset int64, length int64, filename string) error {
+	fmt.Fprintf(p.writer, "Resuming download of \"%s\" from %sB (%sB remaining)\n",
+		filename, bytefmt.ByteSize(uint64(offset)), bytefmt.ByteSize(uint64(length)))
+	return nil
+}
+
+func (p *PlainPrinter) PrintDownloadComplete(filename string) error {
+	fmt.Fprintf(p.writer, "\"%s\" is already complete\n", filename)
+	return nil
+}
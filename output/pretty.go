@@ -2,6 +2,7 @@ package output
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,8 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"code.cloudfoundry.org/bytefmt"
 	"github.com/logrusorgru/aurora"
@@ -17,73 +20,146 @@ import (
 
 type PrettyPrinter struct {
 	writer        io.Writer
-	plain         Printer
+	plain         *PlainPrinter
 	aurora        aurora.Aurora
+	enableColor   bool
+	colorDepth    ColorDepth
+	hyperlinks    bool
 	headerPalette *HeaderPalette
 	jsonPalette   *JSONPalette
-	indentWidth   int
+	format        *FormatOptions
+	limits        JSONLimits
+	binary        BinaryMode
+	protoSchema   *ProtoSchema
+	jwtKey        *JWTKey
+	annotate      bool
+	headerFilter  *HeaderFilter
+	view          View
+	columns       []string
+	terminalWidth int
+	certWarning   int
 }
 
 type PrettyPrinterConfig struct {
 	Writer      io.Writer
 	EnableColor bool
+	ColorDepth  ColorDepth
+	Hyperlinks  bool           // emit OSC 8 hyperlinks for URLs in headers
+	Theme       *Theme         // the default theme is used if nil
+	Format      *FormatOptions // DefaultFormatOptions is used if nil
+	Limits      JSONLimits
+	Binary      BinaryMode
+	ProtoSchema *ProtoSchema // protobuf bodies are decoded without schema if nil
+	JWTKey      *JWTKey      // signatures of JWTs are not verified if nil
+	Annotate    bool         // explain well-known header fields
+	View        View
+	Columns     []string // columns of TableView (all keys are used if empty)
+	// Width of the terminal used to fit tables in. 0 means unlimited.
+	TerminalWidth int
+	// Header fields not matching HeaderFilter are not printed. All fields are printed if nil.
+	HeaderFilter *HeaderFilter
+	// Certificates expiring within this number of days are warned about. 0 disables warnings.
+	CertWarningDays int
 }
 
 type HeaderPalette struct {
-	Method              aurora.Color
-	URL                 aurora.Color
-	Proto               aurora.Color
-	SuccessfulStatus    aurora.Color
-	NonSuccessfulStatus aurora.Color
-	FieldName           aurora.Color
-	FieldValue          aurora.Color
-	FieldSeparator      aurora.Color
+	Method              Color
+	URL                 Color
+	Proto               Color
+	SuccessfulStatus    Color
+	NonSuccessfulStatus Color
+	FieldName           Color
+	FieldValue          Color
+	FieldSeparator      Color
 }
 
 var defaultHeaderPalette = HeaderPalette{
-	Method:              aurora.WhiteFg | aurora.BoldFm,
-	URL:                 aurora.GreenFg | aurora.BoldFm,
-	Proto:               aurora.BlueFg,
-	SuccessfulStatus:    aurora.GreenFg | aurora.BoldFm,
-	NonSuccessfulStatus: aurora.YellowFg | aurora.BoldFm,
-	FieldName:           aurora.WhiteFg,
-	FieldValue:          aurora.CyanFg,
-	FieldSeparator:      aurora.WhiteFg,
+	Method:              basic(aurora.WhiteFg | aurora.BoldFm),
+	URL:                 basic(aurora.GreenFg | aurora.BoldFm),
+	Proto:               basic(aurora.BlueFg),
+	SuccessfulStatus:    basic(aurora.GreenFg | aurora.BoldFm),
+	NonSuccessfulStatus: basic(aurora.YellowFg | aurora.BoldFm),
+	FieldName:           basic(aurora.WhiteFg),
+	FieldValue:          basic(aurora.CyanFg),
+	FieldSeparator:      basic(aurora.WhiteFg),
 }
 
 type JSONPalette struct {
-	Key       aurora.Color
-	String    aurora.Color
-	Number    aurora.Color
-	Boolean   aurora.Color
-	Null      aurora.Color
-	Delimiter aurora.Color
+	Key       Color
+	String    Color
+	Number    Color
+	Boolean   Color
+	Null      Color
+	Delimiter Color
 }
 
 var defaultJSONPalette = JSONPalette{
-	Key:       aurora.BlueFg,
-	String:    aurora.YellowFg,
-	Number:    aurora.CyanFg,
-	Boolean:   aurora.RedFg | aurora.BoldFm,
-	Null:      aurora.RedFg | aurora.BoldFm,
-	Delimiter: aurora.WhiteFg,
+	Key:       basic(aurora.BlueFg),
+	String:    basic(aurora.YellowFg),
+	Number:    basic(aurora.CyanFg),
+	Boolean:   basic(aurora.RedFg | aurora.BoldFm),
+	Null:      basic(aurora.RedFg | aurora.BoldFm),
+	Delimiter: basic(aurora.WhiteFg),
 }
 
 var errMalformedJSON = errors.New("output: malformed json")
 
 func NewPrettyPrinter(config PrettyPrinterConfig) Printer {
+	format := config.Format
+	if format == nil {
+		format = &DefaultFormatOptions
+	}
+	theme := config.Theme
+	if theme == nil {
+		theme = &defaultTheme
+	}
 	return &PrettyPrinter{
-		writer:        config.Writer,
-		plain:         NewPlainPrinter(config.Writer),
+		writer: config.Writer,
+		plain: newPlainPrinter(PlainPrinterConfig{
+			Writer: config.Writer,
+			Format: format,
+		}),
 		aurora:        aurora.NewAurora(config.EnableColor),
-		headerPalette: &defaultHeaderPalette,
-		jsonPalette:   &defaultJSONPalette,
-		indentWidth:   4,
+		enableColor:   config.EnableColor,
+		colorDepth:    config.ColorDepth,
+		hyperlinks:    config.Hyperlinks,
+		headerPalette: &theme.HeaderPalette,
+		jsonPalette:   &theme.JSONPalette,
+		format:        format,
+		limits:        config.Limits,
+		binary:        config.Binary,
+		protoSchema:   config.ProtoSchema,
+		jwtKey:        config.JWTKey,
+		annotate:      config.Annotate,
+		headerFilter:  config.HeaderFilter,
+		view:          config.View,
+		columns:       config.Columns,
+		terminalWidth: config.TerminalWidth,
+		certWarning:   config.CertWarningDays,
+	}
+}
+
+func (p *PrettyPrinter) colorize(arg interface{}, color Color) interface{} {
+	return colorize(p.aurora, p.enableColor, p.colorDepth, arg, color)
+}
+
+func (p *PrettyPrinter) colorizeHexdump(arg interface{}, kind hexdumpByteKind) interface{} {
+	switch kind {
+	case hexdumpOffset:
+		return p.colorize(arg, p.jsonPalette.Key)
+	case hexdumpPrintable:
+		return p.colorize(arg, p.jsonPalette.String)
+	case hexdumpWhitespace:
+		return p.colorize(arg, p.jsonPalette.Number)
+	case hexdumpNUL:
+		return p.colorize(arg, p.jsonPalette.Delimiter)
+	default:
+		return p.colorize(arg, p.jsonPalette.Null)
 	}
 }
 
 func (p *PrettyPrinter) PrintStatusLine(proto string, status string, statusCode int) error {
-	var statusColor aurora.Color
+	var statusColor Color
 	if 200 <= statusCode && statusCode < 300 {
 		statusColor = p.headerPalette.SuccessfulStatus
 	} else {
@@ -91,35 +167,37 @@ func (p *PrettyPrinter) PrintStatusLine(proto string, status string, statusCode
 	}
 
 	fmt.Fprintf(p.writer, "%s %s\n",
-		p.aurora.Colorize(proto, p.headerPalette.Proto),
-		p.aurora.Colorize(status, statusColor),
+		p.colorize(proto, p.headerPalette.Proto),
+		p.colorize(status, statusColor),
 	)
 	return nil
 }
 
 func (p *PrettyPrinter) PrintRequestLine(req *http.Request) error {
 	fmt.Fprintf(p.writer, "%s %s %s\n",
-		p.aurora.Colorize(req.Method, p.headerPalette.Method),
-		p.aurora.Colorize(req.URL, p.headerPalette.URL),
-		p.aurora.Colorize(req.Proto, p.headerPalette.Proto),
+		p.colorize(req.Method, p.headerPalette.Method),
+		p.colorize(req.URL, p.headerPalette.URL),
+		p.colorize(req.Proto, p.headerPalette.Proto),
 	)
 	return nil
 }
 
 func (p *PrettyPrinter) PrintHeader(header http.Header) error {
-	var names []string
-	for name := range header {
-		names = append(names, name)
-	}
-	sort.Strings(names)
-
-	for _, name := range names {
+	now := time.Now()
+	for _, name := range headerNames(header, p.format.HeadersSort) {
+		if !p.headerFilter.Match(name) {
+			continue
+		}
 		values := header[name]
 		for _, value := range values {
+			if p.annotate {
+				writeAnnotatedField(p.writer, name, value, p.formatHeaderValue(name, value), now, p.headerPalette, p.colorize)
+				continue
+			}
 			fmt.Fprintf(p.writer, "%s%s %s\n",
-				p.aurora.Colorize(name, p.headerPalette.FieldName),
-				p.aurora.Colorize(":", p.headerPalette.FieldSeparator),
-				p.aurora.Colorize(value, p.headerPalette.FieldValue))
+				p.colorize(name, p.headerPalette.FieldName),
+				p.colorize(":", p.headerPalette.FieldSeparator),
+				p.formatHeaderValue(name, value))
 		}
 	}
 
@@ -127,6 +205,18 @@ func (p *PrettyPrinter) PrintHeader(header http.Header) error {
 	return nil
 }
 
+func (p *PrettyPrinter) PrintJWTs(header http.Header, body io.Reader, contentType string) (io.Reader, error) {
+	j := &jwtPrinter{
+		writer:     p.writer,
+		palette:    p.headerPalette,
+		colorize:   p.colorize,
+		valueColor: p.tableCellColor,
+		key:        p.jwtKey,
+		now:        time.Now(),
+	}
+	return j.printJWTs(header, body, contentType)
+}
+
 func isJSON(contentType string) bool {
 	contentType = strings.TrimSpace(contentType)
 
@@ -139,9 +229,88 @@ func isJSON(contentType string) bool {
 }
 
 func (p *PrettyPrinter) PrintBody(body io.Reader, contentType string) error {
+	body, boundary, parts, ok, err := readMultipart(body, contentType)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return p.printMultipart(boundary, parts)
+	}
+
+	body, err = decodeBody(body, contentType)
+	if err != nil {
+		return err
+	}
+	body, contentType, err = decodeBinaryJSON(body, contentType)
+	if err != nil {
+		return err
+	}
+	body, contentType, trailer, err := decodeProtobuf(body, contentType, p.protoSchema)
+	if err != nil {
+		return err
+	}
+	if err := p.printBody(body, contentType); err != nil {
+		return err
+	}
+	if len(trailer) > 0 {
+		return p.PrintHeader(trailer)
+	}
+	return nil
+}
+
+func (p *PrettyPrinter) PrintRequestBody(body io.Reader, contentType string) error {
+	form := &formPrinter{
+		writer:   p.writer,
+		palette:  p.headerPalette,
+		colorize: p.colorize,
+		printBody: func(w io.Writer, body io.Reader, contentType string) error {
+			return p.withWriter(w).PrintBody(body, contentType)
+		},
+	}
+	body, printed, err := form.printForm(body, contentType)
+	if printed || err != nil {
+		return err
+	}
+	return p.PrintBody(body, contentType)
+}
+
+// printMultipart prints the header and the body of each part separated by boundary lines.
+func (p *PrettyPrinter) printMultipart(boundary string, parts []bodyPart) error {
+	for _, part := range parts {
+		fmt.Fprintf(p.writer, "%s\n", p.colorize("--"+boundary, p.headerPalette.FieldSeparator))
+		if err := p.PrintHeader(http.Header(part.header)); err != nil {
+			return err
+		}
+		tracker := &newlineTracker{writer: p.writer}
+		if err := p.withWriter(tracker).PrintBody(bytes.NewReader(part.body), partContentType(part)); err != nil {
+			return err
+		}
+		if tracker.needsNewline() {
+			fmt.Fprintln(p.writer)
+		}
+	}
+	fmt.Fprintf(p.writer, "%s\n", p.colorize("--"+boundary+"--", p.headerPalette.FieldSeparator))
+	return nil
+}
+
+// withWriter returns a copy of p that writes to w.
+func (p *PrettyPrinter) withWriter(w io.Writer) *PrettyPrinter {
+	printer := *p
+	printer.writer = w
+	printer.plain = newPlainPrinter(PlainPrinterConfig{Writer: w, Format: p.format})
+	return &printer
+}
+
+func (p *PrettyPrinter) printBody(body io.Reader, contentType string) error {
+	// body has already been converted into UTF-8
+	body, printed, err := printBinary(p.writer, body, "", p.binary, p.colorizeHexdump)
+	if printed || err != nil {
+		return err
+	}
+
 	// Fallback to PlainPrinter when the body is not JSON
 	if !isJSON(contentType) {
-		return p.plain.PrintBody(body, contentType)
+		return p.plain.writeBody(body)
 	}
 
 	content, err := ioutil.ReadAll(body)
@@ -157,17 +326,46 @@ func (p *PrettyPrinter) PrintBody(body io.Reader, contentType string) error {
 		p.writer.Write(content)
 		return nil
 	}
-
-	err = p.printJSON(toks, 0)
-	// errMalformedJSON errors can be ignored. This is because the JSON is
-	// pre-tokenized, and therefore errMalformedJSON errors only occur when
-	// the JSON ends in the middle.
-	if err != nil && !errors.Is(err, errMalformedJSON) {
-		return err
+	if p.format.JSONSortKeys {
+		// Malformed JSON cannot be sorted. In that case, print it in the original order.
+		if sorted, ok := sortObjectKeys(toks.tokens); ok {
+			toks.tokens = sorted
+		}
+	}
+	if isProblemJSON(contentType) {
+		if pr, ok := newProblem(toks.tokens); ok {
+			return p.printProblem(pr)
+		}
+	}
+	if p.view == TableView {
+		// Non-tabular values are printed as JSON
+		t, err := newTable(toks.tokens, p.columns)
+		if err != nil {
+			return err
+		}
+		if t != nil {
+			return p.printTable(t)
+		}
 	}
 
-	fmt.Fprintln(p.writer)
-	return nil
+	// The body may contain multiple JSON values (e.g. JSON Lines or the result of --filter).
+	// Each of them is printed in separate lines.
+	for {
+		err = p.printJSON(toks, 0)
+		fmt.Fprintln(p.writer)
+		// errMalformedJSON errors can be ignored. This is because the JSON is
+		// pre-tokenized, and therefore errMalformedJSON errors only occur when
+		// the JSON ends in the middle.
+		if errors.Is(err, errMalformedJSON) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if toks.pos >= len(toks.tokens) {
+			return nil
+		}
+	}
 }
 
 // newTokenBuffer allows you to create a tokenBuffer which contains all the
@@ -214,6 +412,90 @@ func (t *tokenBuffer) peek() json.Token {
 	return t.tokens[t.pos]
 }
 
+// sortObjectKeys returns a copy of tokens in which the members of every object
+// are ordered by their keys. ok is false if tokens is not a sequence of complete
+// JSON values.
+func sortObjectKeys(tokens []json.Token) (sorted []json.Token, ok bool) {
+	sorted = make([]json.Token, 0, len(tokens))
+	pos := 0
+	for pos < len(tokens) {
+		sorted, pos, ok = appendSortedValue(sorted, tokens, pos)
+		if !ok {
+			return nil, false
+		}
+	}
+	return sorted, true
+}
+
+// appendSortedValue appends the JSON value starting at tokens[pos] to out with
+// its object members sorted, and returns the position next to the value.
+func appendSortedValue(out []json.Token, tokens []json.Token, pos int) ([]json.Token, int, bool) {
+	if pos >= len(tokens) {
+		return nil, pos, false
+	}
+	delim, isDelim := tokens[pos].(json.Delim)
+	if !isDelim {
+		return append(out, tokens[pos]), pos + 1, true
+	}
+
+	switch delim {
+	case '[':
+		out = append(out, delim)
+		pos++
+		for {
+			if pos >= len(tokens) {
+				return nil, pos, false
+			}
+			if tokens[pos] == json.Delim(']') {
+				return append(out, tokens[pos]), pos + 1, true
+			}
+			var ok bool
+			out, pos, ok = appendSortedValue(out, tokens, pos)
+			if !ok {
+				return nil, pos, false
+			}
+		}
+	case '{':
+		type member struct {
+			key   string
+			value []json.Token
+		}
+		var members []member
+		pos++
+		for {
+			if pos >= len(tokens) {
+				return nil, pos, false
+			}
+			if tokens[pos] == json.Delim('}') {
+				pos++
+				break
+			}
+			key, ok := tokens[pos].(string)
+			if !ok {
+				return nil, pos, false
+			}
+			var value []json.Token
+			value, pos, ok = appendSortedValue(nil, tokens, pos+1)
+			if !ok {
+				return nil, pos, false
+			}
+			members = append(members, member{key: key, value: value})
+		}
+		sort.SliceStable(members, func(i, j int) bool {
+			return members[i].key < members[j].key
+		})
+
+		out = append(out, delim)
+		for _, m := range members {
+			out = append(out, m.key)
+			out = append(out, m.value...)
+		}
+		return append(out, json.Delim('}')), pos, true
+	default:
+		return nil, pos, false
+	}
+}
+
 func (p *PrettyPrinter) printJSON(buf *tokenBuffer, depth int) error {
 	switch v := buf.token().(type) {
 	case json.Delim:
@@ -241,7 +523,7 @@ func (p *PrettyPrinter) printJSON(buf *tokenBuffer, depth int) error {
 }
 
 func (p *PrettyPrinter) printNull() error {
-	fmt.Fprintf(p.writer, "%s", p.aurora.Colorize("null", p.jsonPalette.Null))
+	fmt.Fprintf(p.writer, "%s", p.colorize("null", p.jsonPalette.Null))
 	return nil
 }
 
@@ -252,34 +534,97 @@ func (p *PrettyPrinter) printBool(v bool) error {
 	} else {
 		s = "false"
 	}
-	fmt.Fprintf(p.writer, "%s", p.aurora.Colorize(s, p.jsonPalette.Boolean))
+	fmt.Fprintf(p.writer, "%s", p.colorize(s, p.jsonPalette.Boolean))
 	return nil
 }
 
 func (p *PrettyPrinter) printNumber(n json.Number) error {
-	fmt.Fprintf(p.writer, "%s", p.aurora.Colorize(n.String(), p.jsonPalette.Number))
+	fmt.Fprintf(p.writer, "%s", p.colorize(n.String(), p.jsonPalette.Number))
 	return nil
 }
 
 func (p *PrettyPrinter) printString(s string) error {
+	maxLength := p.limits.MaxStringLength
+	if maxLength > 0 && utf8.RuneCountInString(s) > maxLength {
+		runes := []rune(s)
+		b, _ := json.Marshal(string(runes[:maxLength]) + "…")
+		fmt.Fprintf(p.writer, "%s %s",
+			p.colorize(string(b), p.jsonPalette.String),
+			p.colorize(fmt.Sprintf("(+%d chars)", len(runes)-maxLength), p.jsonPalette.Delimiter))
+		return nil
+	}
+
 	b, _ := json.Marshal(s)
-	fmt.Fprintf(p.writer, "%s", p.aurora.Colorize(string(b), p.jsonPalette.String))
+	fmt.Fprintf(p.writer, "%s", p.colorize(string(b), p.jsonPalette.String))
+	return nil
+}
+
+// skipValues skips the rest of the array or the object whose opening delimiter
+// has been read, and returns the number of items (or members) skipped.
+func skipValues(buf *tokenBuffer, isObject bool) (int, error) {
+	n := 0
+	for {
+		switch buf.peek() {
+		case json.Delim(']'), json.Delim('}'):
+			buf.token()
+			return n, nil
+		case endOfBody{}:
+			return n, errMalformedJSON
+		}
+		if isObject {
+			buf.token() // key
+		}
+		end, ok := valueEnd(buf.tokens, buf.pos)
+		buf.pos = end
+		if !ok {
+			return n, errMalformedJSON
+		}
+		n++
+	}
+}
+
+// printCollapsed prints the rest of an array or an object deeper than MaxDepth
+// like `… 3 items]`. The opening bracket must be already printed.
+func (p *PrettyPrinter) printCollapsed(buf *tokenBuffer, isObject bool) error {
+	n, err := skipValues(buf, isObject)
+	close, unit := "]", "item"
+	if isObject {
+		close, unit = "}", "key"
+	}
+	fmt.Fprintf(p.writer, "%s", p.colorize("… "+pluralize(n, unit), p.jsonPalette.Delimiter))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(p.writer, "%s", p.colorize(close, p.jsonPalette.Delimiter))
 	return nil
 }
 
 func (p *PrettyPrinter) printArray(buf *tokenBuffer, depth int) error {
-	fmt.Fprintf(p.writer, "%s", p.aurora.Colorize("[", p.jsonPalette.Delimiter))
+	fmt.Fprintf(p.writer, "%s", p.colorize("[", p.jsonPalette.Delimiter))
 
 	// fast path: array is empty
 	if d, ok := buf.peek().(json.Delim); ok && d == ']' {
 		buf.token()
-		fmt.Fprintf(p.writer, "%s", p.aurora.Colorize("]", p.jsonPalette.Delimiter))
+		fmt.Fprintf(p.writer, "%s", p.colorize("]", p.jsonPalette.Delimiter))
 		return nil
 	}
 
-	for {
+	if p.limits.MaxDepth > 0 && depth >= p.limits.MaxDepth {
+		return p.printCollapsed(buf, false)
+	}
+
+	for i := 0; ; i++ {
 		p.breakLine(depth + 1)
 
+		if p.limits.MaxArrayItems > 0 && i >= p.limits.MaxArrayItems {
+			n, err := skipValues(buf, false)
+			fmt.Fprintf(p.writer, "%s", p.colorize("… "+pluralize(n, "more item"), p.jsonPalette.Delimiter))
+			if err != nil {
+				return err
+			}
+			break
+		}
+
 		if err := p.printJSON(buf, depth+1); err != nil {
 			return err
 		}
@@ -289,24 +634,28 @@ func (p *PrettyPrinter) printArray(buf *tokenBuffer, depth int) error {
 			buf.token()
 			break
 		}
-		fmt.Fprintf(p.writer, "%s", p.aurora.Colorize(",", p.jsonPalette.Delimiter))
+		fmt.Fprintf(p.writer, "%s", p.colorize(",", p.jsonPalette.Delimiter))
 	}
 
 	p.breakLine(depth)
-	fmt.Fprintf(p.writer, "%s", p.aurora.Colorize("]", p.jsonPalette.Delimiter))
+	fmt.Fprintf(p.writer, "%s", p.colorize("]", p.jsonPalette.Delimiter))
 	return nil
 }
 
 func (p *PrettyPrinter) printMap(buf *tokenBuffer, depth int) error {
-	fmt.Fprintf(p.writer, "%s", p.aurora.Colorize("{", p.jsonPalette.Delimiter))
+	fmt.Fprintf(p.writer, "%s", p.colorize("{", p.jsonPalette.Delimiter))
 
 	// fast path: object is empty
 	if d, ok := buf.peek().(json.Delim); ok && d == '}' {
 		buf.token()
-		fmt.Fprintf(p.writer, "%s", p.aurora.Colorize("}", p.jsonPalette.Delimiter))
+		fmt.Fprintf(p.writer, "%s", p.colorize("}", p.jsonPalette.Delimiter))
 		return nil
 	}
 
+	if p.limits.MaxDepth > 0 && depth >= p.limits.MaxDepth {
+		return p.printCollapsed(buf, true)
+	}
+
 	for {
 		p.breakLine(depth + 1)
 
@@ -316,8 +665,8 @@ func (p *PrettyPrinter) printMap(buf *tokenBuffer, depth int) error {
 		}
 		encodedKey, _ := json.Marshal(key)
 		fmt.Fprintf(p.writer, "%s%s ",
-			p.aurora.Colorize(encodedKey, p.jsonPalette.Key),
-			p.aurora.Colorize(":", p.jsonPalette.Delimiter))
+			p.colorize(encodedKey, p.jsonPalette.Key),
+			p.colorize(":", p.jsonPalette.Delimiter))
 
 		if err := p.printJSON(buf, depth+1); err != nil {
 			return err
@@ -328,19 +677,59 @@ func (p *PrettyPrinter) printMap(buf *tokenBuffer, depth int) error {
 			buf.token()
 			break
 		}
-		fmt.Fprintf(p.writer, "%s", p.aurora.Colorize(",", p.jsonPalette.Delimiter))
+		fmt.Fprintf(p.writer, "%s", p.colorize(",", p.jsonPalette.Delimiter))
 	}
 
 	p.breakLine(depth)
-	fmt.Fprintf(p.writer, "%s", p.aurora.Colorize("}", p.jsonPalette.Delimiter))
+	fmt.Fprintf(p.writer, "%s", p.colorize("}", p.jsonPalette.Delimiter))
 	return nil
 }
 
 func (p *PrettyPrinter) breakLine(depth int) {
-	fmt.Fprintf(p.writer, "\n%s", strings.Repeat(" ", depth*p.indentWidth))
+	fmt.Fprintf(p.writer, "\n%s", strings.Repeat(" ", depth*p.format.JSONIndent))
+}
+
+func (p *PrettyPrinter) PrintMetadata(meta *Metadata) error {
+	writeMetadata(p.writer, meta, p.headerPalette, p.colorize)
+	return nil
+}
+
+func (p *PrettyPrinter) PrintTiming(timing *Timing) error {
+	barColors := []Color{
+		p.jsonPalette.Key,
+		p.jsonPalette.String,
+		p.jsonPalette.Number,
+		p.jsonPalette.Boolean,
+		p.headerPalette.SuccessfulStatus,
+	}
+	writeTimingWaterfall(p.writer, timing, p.headerPalette, barColors, p.colorize)
+	return nil
+}
+
+func (p *PrettyPrinter) PrintTLS(state *tls.ConnectionState) error {
+	t := &tlsPrinter{
+		writer:      p.writer,
+		palette:     p.headerPalette,
+		colorize:    p.colorize,
+		warningDays: p.certWarning,
+		now:         time.Now(),
+	}
+	t.printTLS(state)
+	return nil
 }
 
 func (p *PrettyPrinter) PrintDownload(length int64, filename string) error {
 	fmt.Fprintf(p.writer, "Downloading %sB to \"%s\"\n", bytefmt.ByteSize(uint64(length)), filename)
 	return nil
 }
+
+func (p *PrettyPrinter) PrintResume(offset int64, length int64, filename string) error {
+	fmt.Fprintf(p.writer, "Resuming download of \"%s\" from %sB (%sB remaining)\n",
+		filename, bytefmt.ByteSize(uint64(offset)), bytefmt.ByteSize(uint64(length)))
+	return nil
+}
+
+func (p *PrettyPrinter) PrintDownloadComplete(filename string) error {
+	fmt.Fprintf(p.writer, "\"%s\" is already complete\n", filename)
+	return nil
+}
@@ -169,6 +169,18 @@ func TestPrettyPrinter_PrintBody(t *testing.T) {
 			body:     `[1`,
 			expected: "[\n    1,\n    \n",
 		},
+		{
+			title: "Multiple values",
+			body:  "{\"id\": 1}\n[]\n\"foo\"",
+			expected: strings.Join([]string{
+				`{`,
+				`    "id": 1`,
+				`}`,
+				`[]`,
+				`"foo"`,
+				``,
+			}, "\n"),
+		},
 		{
 			title: "Malformed JSON 4",
 			body:  `{"hello": "world"`,
@@ -204,6 +216,251 @@ func TestPrettyPrinter_PrintBody(t *testing.T) {
 	}
 }
 
+func TestPrettyPrinter_PrintHeader_Unsorted(t *testing.T) {
+	// Setup
+	var buffer strings.Builder
+	printer := NewPrettyPrinter(PrettyPrinterConfig{
+		Writer:      &buffer,
+		EnableColor: false,
+		Format:      &FormatOptions{JSONIndent: 4, HeadersSort: false},
+	})
+	header := http.Header{
+		"X-Foo": []string{"hello", "world"},
+	}
+
+	// Exercise
+	err := printer.PrintHeader(header)
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Verify
+	expected := "X-Foo: hello\nX-Foo: world\n\n"
+	if buffer.String() != expected {
+		t.Errorf("unexpected output: expected=\n%s\nactual=\n%s\n", expected, buffer.String())
+	}
+}
+
+func TestNewPrinter_DefaultFormatOptions(t *testing.T) {
+	// Setup: Options without Format
+	var buffer strings.Builder
+	printer := NewPrinter(&buffer, &Options{EnableFormat: true})
+	header := http.Header{
+		"X-B": []string{"b"},
+		"X-A": []string{"a"},
+	}
+
+	// Exercise
+	if err := printer.PrintHeader(header); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	if err := printer.PrintBody(strings.NewReader(`{"a":1}`), "application/json"); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Verify: headers are sorted and JSON is indented by 4 spaces
+	expected := "X-A: a\nX-B: b\n\n{\n    \"a\": 1\n}\n"
+	if buffer.String() != expected {
+		t.Errorf("unexpected output: expected=\n%s\nactual=\n%s\n", expected, buffer.String())
+	}
+}
+
+func TestPrettyPrinter_PrintBody_FormatOptions(t *testing.T) {
+	testCases := []struct {
+		title    string
+		format   FormatOptions
+		body     string
+		expected string
+	}{
+		{
+			title:  "Indent 2",
+			format: FormatOptions{JSONIndent: 2},
+			body:   `{"foo": [1, {"bar": null}]}`,
+			expected: strings.Join([]string{
+				`{`,
+				`  "foo": [`,
+				`    1,`,
+				`    {`,
+				`      "bar": null`,
+				`    }`,
+				`  ]`,
+				"}\n",
+			}, "\n"),
+		},
+		{
+			title:  "Indent 0",
+			format: FormatOptions{JSONIndent: 0},
+			body:   `{"foo": [1]}`,
+			expected: strings.Join([]string{
+				`{`,
+				`"foo": [`,
+				`1`,
+				`]`,
+				"}\n",
+			}, "\n"),
+		},
+		{
+			title:  "Sort keys",
+			format: FormatOptions{JSONIndent: 4, JSONSortKeys: true},
+			body:   `{"zzz": {"b": 1, "a": 2}, "aaa": [{"y": true, "x": false}], "": null}`,
+			expected: strings.Join([]string{
+				`{`,
+				`    "": null,`,
+				`    "aaa": [`,
+				`        {`,
+				`            "x": false,`,
+				`            "y": true`,
+				`        }`,
+				`    ],`,
+				`    "zzz": {`,
+				`        "a": 2,`,
+				`        "b": 1`,
+				`    }`,
+				"}\n",
+			}, "\n"),
+		},
+		{
+			title:  "Sort keys of malformed JSON",
+			format: FormatOptions{JSONIndent: 4, JSONSortKeys: true},
+			body:   `{"b": 1, "a": 2`,
+			expected: strings.Join([]string{
+				`{`,
+				`    "b": 1,`,
+				`    "a": 2,`,
+				`    `,
+				``,
+			}, "\n"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var buffer strings.Builder
+			format := tt.format
+			printer := NewPrettyPrinter(PrettyPrinterConfig{
+				Writer:      &buffer,
+				EnableColor: false,
+				Format:      &format,
+			})
+
+			// Exercise
+			err := printer.PrintBody(strings.NewReader(tt.body), "application/json")
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s\n", tt.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestPrettyPrinter_PrintBody_Limits(t *testing.T) {
+	testCases := []struct {
+		title    string
+		limits   JSONLimits
+		body     string
+		expected string
+	}{
+		{
+			title:  "Max depth",
+			limits: JSONLimits{MaxDepth: 1},
+			body:   `{"a": [1, 2, 3], "b": {"c": {}}, "c": [], "d": [{}], "e": 1}`,
+			expected: strings.Join([]string{
+				`{`,
+				`    "a": [… 3 items],`,
+				`    "b": {… 1 key},`,
+				`    "c": [],`,
+				`    "d": [… 1 item],`,
+				`    "e": 1`,
+				"}\n",
+			}, "\n"),
+		},
+		{
+			title:  "Max depth 0 means unlimited",
+			limits: JSONLimits{MaxDepth: 0},
+			body:   `[[1]]`,
+			expected: strings.Join([]string{
+				`[`,
+				`    [`,
+				`        1`,
+				`    ]`,
+				"]\n",
+			}, "\n"),
+		},
+		{
+			title:  "Max array items",
+			limits: JSONLimits{MaxArrayItems: 2},
+			body:   `[1, [2, 3, 4], {"x": 5}, 6]`,
+			expected: strings.Join([]string{
+				`[`,
+				`    1,`,
+				`    [`,
+				`        2,`,
+				`        3,`,
+				`        … 1 more item`,
+				`    ],`,
+				`    … 2 more items`,
+				"]\n",
+			}, "\n"),
+		},
+		{
+			title:  "Max array items (not exceeded)",
+			limits: JSONLimits{MaxArrayItems: 2},
+			body:   `[1, 2]`,
+			expected: strings.Join([]string{
+				`[`,
+				`    1,`,
+				`    2`,
+				"]\n",
+			}, "\n"),
+		},
+		{
+			title:  "Max string length",
+			limits: JSONLimits{MaxStringLength: 3},
+			body:   `{"long key": "🍣🍣🍣🍣🍣", "short": "abc"}`,
+			expected: strings.Join([]string{
+				`{`,
+				`    "long key": "🍣🍣🍣…" (+2 chars),`,
+				`    "short": "abc"`,
+				"}\n",
+			}, "\n"),
+		},
+		{
+			title:    "Collapse malformed JSON",
+			limits:   JSONLimits{MaxDepth: 1},
+			body:     `{"a": [1, 2`,
+			expected: "{\n    \"a\": [… 2 items\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var buffer strings.Builder
+			printer := NewPrettyPrinter(PrettyPrinterConfig{
+				Writer:      &buffer,
+				EnableColor: false,
+				Limits:      tt.limits,
+			})
+
+			// Exercise
+			err := printer.PrintBody(strings.NewReader(tt.body), "application/json")
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s\n", tt.expected, buffer.String())
+			}
+		})
+	}
+}
+
 func TestPrettyPrinter_DetectJSON(t *testing.T) {
 	if !isJSON("application/json") {
 		t.Errorf("didn't detect application/json as JSON")
@@ -1,8 +1,10 @@
 package output
 
 import (
+	"crypto/tls"
 	"io"
 	"net/http"
+	"sort"
 )
 
 type Printer interface {
@@ -13,13 +15,145 @@ type Printer interface {
 	PrintDownload(length int64, filename string) error
 }
 
+// The following interfaces are optionally implemented by a Printer. Use the
+// functions of the same names to call them, which fall back to the methods of
+// Printer or do nothing if the printer does not implement them.
+
+// RequestBodyPrinter prints request bodies, where form bodies are printed in
+// decoded views.
+type RequestBodyPrinter interface {
+	PrintRequestBody(body io.Reader, contentType string) error
+}
+
+// JWTPrinter prints JWTs found in header and body. The returned reader must be
+// used to read body afterwards. body may be nil.
+type JWTPrinter interface {
+	PrintJWTs(header http.Header, body io.Reader, contentType string) (io.Reader, error)
+}
+
+// ResumePrinter prints the progress of resumed downloads.
+type ResumePrinter interface {
+	// PrintResume is printed instead of PrintDownload when a partial file is resumed.
+	PrintResume(offset int64, length int64, filename string) error
+	PrintDownloadComplete(filename string) error
+}
+
+// MetadataPrinter prints metadata of the exchange.
+type MetadataPrinter interface {
+	PrintMetadata(meta *Metadata) error
+}
+
+// TimingPrinter prints the timing breakdown of the exchange.
+type TimingPrinter interface {
+	PrintTiming(timing *Timing) error
+}
+
+// TLSPrinter prints the TLS session and the certificate chain. state is nil
+// if TLS is not used.
+type TLSPrinter interface {
+	PrintTLS(state *tls.ConnectionState) error
+}
+
+// PrintRequestBody prints body with p as RequestBodyPrinter, or as Printer.PrintBody.
+func PrintRequestBody(p Printer, body io.Reader, contentType string) error {
+	if rp, ok := p.(RequestBodyPrinter); ok {
+		return rp.PrintRequestBody(body, contentType)
+	}
+	return p.PrintBody(body, contentType)
+}
+
+// PrintJWTs prints JWTs with p as JWTPrinter. It returns body as it is if p
+// does not implement JWTPrinter.
+func PrintJWTs(p Printer, header http.Header, body io.Reader, contentType string) (io.Reader, error) {
+	if jp, ok := p.(JWTPrinter); ok {
+		return jp.PrintJWTs(header, body, contentType)
+	}
+	return body, nil
+}
+
+// PrintResume prints a resumed download with p as ResumePrinter, or as
+// Printer.PrintDownload.
+func PrintResume(p Printer, offset int64, length int64, filename string) error {
+	if rp, ok := p.(ResumePrinter); ok {
+		return rp.PrintResume(offset, length, filename)
+	}
+	return p.PrintDownload(length, filename)
+}
+
+// PrintDownloadComplete prints that the download has already completed with p
+// as ResumePrinter.
+func PrintDownloadComplete(p Printer, filename string) error {
+	if rp, ok := p.(ResumePrinter); ok {
+		return rp.PrintDownloadComplete(filename)
+	}
+	return nil
+}
+
+// PrintMetadata prints meta with p as MetadataPrinter.
+func PrintMetadata(p Printer, meta *Metadata) error {
+	if mp, ok := p.(MetadataPrinter); ok {
+		return mp.PrintMetadata(meta)
+	}
+	return nil
+}
+
+// PrintTiming prints timing with p as TimingPrinter.
+func PrintTiming(p Printer, timing *Timing) error {
+	if tp, ok := p.(TimingPrinter); ok {
+		return tp.PrintTiming(timing)
+	}
+	return nil
+}
+
+// PrintTLS prints state with p as TLSPrinter.
+func PrintTLS(p Printer, state *tls.ConnectionState) error {
+	if tp, ok := p.(TLSPrinter); ok {
+		return tp.PrintTLS(state)
+	}
+	return nil
+}
+
 func NewPrinter(w io.Writer, options *Options) Printer {
 	if options.EnableFormat {
 		return NewPrettyPrinter(PrettyPrinterConfig{
-			Writer:      w,
-			EnableColor: options.EnableColor,
+			Writer:          w,
+			EnableColor:     options.EnableColor,
+			ColorDepth:      options.ColorDepth,
+			Hyperlinks:      options.Hyperlinks,
+			Theme:           options.Theme,
+			Format:          options.Format,
+			Limits:          options.Limits,
+			Binary:          options.Binary,
+			ProtoSchema:     options.ProtoSchema,
+			JWTKey:          options.JWTKey,
+			Annotate:        options.AnnotateHeaders,
+			HeaderFilter:    options.HeaderFilter,
+			View:            options.View,
+			Columns:         options.Columns,
+			TerminalWidth:   options.TerminalWidth,
+			CertWarningDays: options.CertWarningDays,
 		})
 	} else {
-		return NewPlainPrinter(w)
+		return NewPlainPrinterWithConfig(PlainPrinterConfig{
+			Writer:          w,
+			Format:          options.Format,
+			Binary:          options.Binary,
+			JWTKey:          options.JWTKey,
+			Annotate:        options.AnnotateHeaders,
+			HeaderFilter:    options.HeaderFilter,
+			CertWarningDays: options.CertWarningDays,
+		})
+	}
+}
+
+// headerNames returns the field names of header, sorted alphabetically if sorted is true.
+func headerNames(header http.Header, sorted bool) []string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	if sorted {
+		sort.Strings(names)
 	}
+	return names
 }
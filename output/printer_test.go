@@ -0,0 +1,79 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// basicPrinter implements only the methods of Printer.
+type basicPrinter struct {
+	calls []string
+}
+
+func (p *basicPrinter) PrintStatusLine(proto string, status string, statusCode int) error {
+	p.calls = append(p.calls, "StatusLine")
+	return nil
+}
+
+func (p *basicPrinter) PrintRequestLine(request *http.Request) error {
+	p.calls = append(p.calls, "RequestLine")
+	return nil
+}
+
+func (p *basicPrinter) PrintHeader(header http.Header) error {
+	p.calls = append(p.calls, "Header")
+	return nil
+}
+
+func (p *basicPrinter) PrintBody(body io.Reader, contentType string) error {
+	data, err := ioutil.ReadAll(body)
+	p.calls = append(p.calls, fmt.Sprintf("Body %s", data))
+	return err
+}
+
+func (p *basicPrinter) PrintDownload(length int64, filename string) error {
+	p.calls = append(p.calls, fmt.Sprintf("Download %d %s", length, filename))
+	return nil
+}
+
+func TestOptionalPrinters_Fallback(t *testing.T) {
+	// Setup
+	printer := &basicPrinter{}
+
+	// Exercise
+	if err := PrintRequestBody(printer, strings.NewReader("a=1"), "application/x-www-form-urlencoded"); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	body, err := PrintJWTs(printer, http.Header{}, strings.NewReader("body"), "text/plain")
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	if data, _ := ioutil.ReadAll(body); string(data) != "body" {
+		t.Errorf("body should be returned as it is: %q", data)
+	}
+	if err := PrintResume(printer, 10, 20, "data"); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	if err := PrintDownloadComplete(printer, "data"); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	if err := PrintMetadata(printer, &Metadata{}); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	if err := PrintTiming(printer, &Timing{}); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+	if err := PrintTLS(printer, nil); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Verify
+	expected := []string{"Body a=1", "Download 20 data"}
+	if strings.Join(printer.calls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected calls: expected=%q, actual=%q", expected, printer.calls)
+	}
+}
@@ -0,0 +1,130 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// problem is a Problem Details document (RFC 7807).
+type problem struct {
+	title    string
+	status   string
+	members  []problemMember // type, detail and instance
+	extended []problemMember // extension members
+}
+
+type problemMember struct {
+	name  string
+	value tableCell
+}
+
+func isProblemJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/problem+json"
+}
+
+// newProblem builds a problem from tokens if tokens is a single JSON object.
+func newProblem(tokens []json.Token) (*problem, bool) {
+	end, ok := valueEnd(tokens, 0)
+	if !ok || end != len(tokens) || kindOfValue(tokens) != "object" {
+		return nil, false
+	}
+
+	pr := &problem{}
+	keys, values := children(tokens)
+	for i, key := range keys {
+		cell := newTableCell(values[i])
+		switch {
+		case key == "title" && cell.kind == "string":
+			pr.title = cell.text
+		case key == "status" && cell.kind == "number":
+			pr.status = cell.text
+		case (key == "type" || key == "detail" || key == "instance") && cell.kind == "string":
+			pr.members = append(pr.members, problemMember{name: key, value: cell})
+		default:
+			pr.extended = append(pr.extended, problemMember{name: key, value: cell})
+		}
+	}
+	return pr, true
+}
+
+// headline returns the status and the title like "403 Out of credit".
+func (pr *problem) headline() string {
+	return strings.TrimSpace(pr.status + " " + pr.title)
+}
+
+func (pr *problem) member(name string) string {
+	for _, m := range pr.members {
+		if m.name == name {
+			return m.value.text
+		}
+	}
+	return ""
+}
+
+// ProblemSummary returns a one-line summary of a Problem Details document
+// like "403 Out of credit - Your current balance is 30, but that costs 50."
+// It returns false if content is not a problem document. content is decoded
+// according to the charset parameter of contentType.
+func ProblemSummary(content []byte, contentType string) (string, bool) {
+	if !isProblemJSON(contentType) {
+		return "", false
+	}
+	body, err := decodeBody(bytes.NewReader(content), contentType)
+	if err != nil {
+		return "", false
+	}
+	toks, err := newTokenBuffer(json.NewDecoder(body))
+	if err != nil || len(toks.tokens) == 0 {
+		return "", false
+	}
+	pr, ok := newProblem(toks.tokens)
+	if !ok {
+		return "", false
+	}
+
+	summary := pr.headline()
+	if detail := pr.member("detail"); detail != "" {
+		if summary != "" {
+			summary += " - "
+		}
+		summary += detail
+	}
+	if summary == "" {
+		summary = pr.member("type")
+	}
+	return summary, summary != ""
+}
+
+// printProblem prints the status and the title in the first line, followed by
+// the other members aligned in columns.
+func (p *PrettyPrinter) printProblem(pr *problem) error {
+	if headline := pr.headline(); headline != "" {
+		fmt.Fprintln(p.writer, p.colorize(headline, p.headerPalette.NonSuccessfulStatus))
+	}
+
+	members := append(append([]problemMember{}, pr.members...), pr.extended...)
+	width := 0
+	for _, m := range members {
+		if w := runewidth.StringWidth(m.name); w > width {
+			width = w
+		}
+	}
+	for _, m := range members {
+		color := p.headerPalette.FieldValue
+		if m.value.kind != "string" {
+			color = p.tableCellColor(m.value.kind)
+		}
+		fmt.Fprintf(p.writer, "%s%s %s%s\n",
+			p.colorize(m.name, p.headerPalette.FieldName),
+			p.colorize(":", p.headerPalette.FieldSeparator),
+			strings.Repeat(" ", width-runewidth.StringWidth(m.name)),
+			p.colorize(m.value.text, color))
+	}
+	return nil
+}
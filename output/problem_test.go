@@ -0,0 +1,124 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrettyPrinter_PrintBody_Problem(t *testing.T) {
+	testCases := []struct {
+		title       string
+		body        string
+		contentType string
+		expected    string
+	}{
+		{
+			title:       "Problem Details",
+			body:        `{"type": "https://example.com/probs/out-of-credit", "title": "Out of credit", "status": 403, "detail": "Your balance is 30.", "instance": "/account/1", "balance": 30, "accounts": ["/account/1"]}`,
+			contentType: "application/problem+json",
+			expected: strings.Join([]string{
+				"403 Out of credit",
+				"type:     https://example.com/probs/out-of-credit",
+				"detail:   Your balance is 30.",
+				"instance: /account/1",
+				"balance:  30",
+				`accounts: ["/account/1"]`,
+				"",
+			}, "\n"),
+		},
+		{
+			title:       "Without title and status",
+			body:        `{"type": "about:blank", "status": "403"}`,
+			contentType: "application/problem+json; charset=utf-8",
+			expected: strings.Join([]string{
+				"type:   about:blank",
+				"status: 403",
+				"",
+			}, "\n"),
+		},
+		{
+			title:       "Not an object",
+			body:        `["foo"]`,
+			contentType: "application/problem+json",
+			expected:    "[\n    \"foo\"\n]\n",
+		},
+		{
+			title:       "Not a problem",
+			body:        `{"title": "foo"}`,
+			contentType: "application/json",
+			expected:    "{\n    \"title\": \"foo\"\n}\n",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var buffer strings.Builder
+			printer := NewPrettyPrinter(PrettyPrinterConfig{Writer: &buffer})
+
+			// Exercise
+			if err := printer.PrintBody(strings.NewReader(tt.body), tt.contentType); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s", tt.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestProblemSummary(t *testing.T) {
+	testCases := []struct {
+		body        string
+		contentType string
+		expected    string
+		expectedOK  bool
+	}{
+		{
+			body:        `{"title": "Out of credit", "status": 403, "detail": "Your balance is 30."}`,
+			contentType: "application/problem+json",
+			expected:    "403 Out of credit - Your balance is 30.",
+			expectedOK:  true,
+		},
+		{
+			body:        `{"status": 404}`,
+			contentType: "application/problem+json",
+			expected:    "404",
+			expectedOK:  true,
+		},
+		{
+			body:        `{"type": "https://example.com/probs/out-of-credit"}`,
+			contentType: "application/problem+json",
+			expected:    "https://example.com/probs/out-of-credit",
+			expectedOK:  true,
+		},
+		{
+			body:        `{}`,
+			contentType: "application/problem+json",
+			expectedOK:  false,
+		},
+		{
+			body:        `{"title": "Out of credit"}`,
+			contentType: "application/json",
+			expectedOK:  false,
+		},
+		{
+			body:        `{"title": `,
+			contentType: "application/problem+json",
+			expectedOK:  false,
+		},
+		{
+			body:        "{\"title\": \"\x8c\xa0\x8c\xc0\x82\xaa\x82\xa0\x82\xe8\x82\xdc\x82\xb9\x82\xf1\"}",
+			contentType: "application/problem+json; charset=Shift_JIS",
+			expected:    "権限がありません",
+			expectedOK:  true,
+		},
+	}
+	for _, tt := range testCases {
+		actual, ok := ProblemSummary([]byte(tt.body), tt.contentType)
+		if actual != tt.expected || ok != tt.expectedOK {
+			t.Errorf("unexpected summary of %s: expected=(%q, %v), actual=(%q, %v)", tt.body, tt.expected, tt.expectedOK, actual, ok)
+		}
+	}
+}
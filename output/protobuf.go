@@ -0,0 +1,263 @@
+package output
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ProtoSchema is a message type used to decode protobuf bodies.
+type ProtoSchema struct {
+	message protoreflect.MessageDescriptor
+}
+
+// LoadProtoSchema loads the message named messageName (e.g. "pkg.Msg") from a
+// FileDescriptorSet file such as the output of `protoc --descriptor_set_out`.
+func LoadProtoSchema(descriptorSetPath string, messageName string) (*ProtoSchema, error) {
+	data, err := ioutil.ReadFile(descriptorSetPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading descriptor set '%s'", descriptorSetPath)
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, errors.Wrapf(err, "parsing descriptor set '%s'", descriptorSetPath)
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing descriptor set '%s'", descriptorSetPath)
+	}
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(messageName))
+	if err != nil {
+		return nil, errors.Errorf("message '%s' is not found in '%s'", messageName, descriptorSetPath)
+	}
+	message, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, errors.Errorf("'%s' is not a message", messageName)
+	}
+	return &ProtoSchema{message: message}, nil
+}
+
+func isProtobuf(mediaType string) bool {
+	return mediaType == "application/x-protobuf" || mediaType == "application/protobuf" ||
+		mediaType == "application/vnd.google.protobuf"
+}
+
+func isGRPCWeb(mediaType string) bool {
+	return mediaType == "application/grpc-web" || mediaType == "application/grpc-web+proto"
+}
+
+// decodeProtobuf converts protobuf bodies into JSON so that they are printed in
+// the same way as JSON bodies. Messages are decoded with schema, or shown in
+// the schema-less wire-format view if schema is nil. gRPC-Web bodies may
+// contain multiple messages and trailers; trailers are returned separately.
+// Other bodies, including malformed ones, are returned as they are.
+func decodeProtobuf(body io.Reader, contentType string, schema *ProtoSchema) (io.Reader, string, http.Header, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !(isProtobuf(mediaType) || isGRPCWeb(mediaType)) {
+		return body, contentType, nil, nil
+	}
+
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, "", nil, errors.Wrap(err, "reading body")
+	}
+	original := bytes.NewReader(content)
+
+	messages := [][]byte{content}
+	var trailer http.Header
+	if isGRPCWeb(mediaType) {
+		messages, trailer, err = splitGRPCWebFrames(content)
+		if err != nil {
+			return original, contentType, nil, nil
+		}
+	}
+
+	var buffer bytes.Buffer
+	for _, message := range messages {
+		encoded, err := protoToJSON(message, schema)
+		if err != nil {
+			return original, contentType, nil, nil
+		}
+		buffer.Write(encoded)
+		buffer.WriteByte('\n')
+	}
+	return &buffer, "application/json", trailer, nil
+}
+
+// splitGRPCWebFrames splits a gRPC-Web body into messages and trailers.
+// Each frame consists of a flag byte, a 4-byte big-endian length and data.
+func splitGRPCWebFrames(content []byte) ([][]byte, http.Header, error) {
+	var messages [][]byte
+	trailer := http.Header{}
+	for len(content) > 0 {
+		if len(content) < 5 {
+			return nil, nil, errors.New("truncated gRPC-Web frame")
+		}
+		flag := content[0]
+		length := binary.BigEndian.Uint32(content[1:5])
+		if uint64(len(content)-5) < uint64(length) {
+			return nil, nil, errors.New("truncated gRPC-Web frame")
+		}
+		data := content[5 : 5+length]
+		content = content[5+length:]
+
+		switch {
+		case flag&0x01 != 0:
+			return nil, nil, errors.New("compressed gRPC-Web frames are not supported")
+		case flag&0x80 != 0:
+			for _, line := range strings.Split(string(data), "\r\n") {
+				i := strings.Index(line, ":")
+				if i < 0 {
+					continue
+				}
+				name := strings.TrimSpace(line[:i])
+				trailer[name] = append(trailer[name], strings.TrimSpace(line[i+1:]))
+			}
+		default:
+			messages = append(messages, data)
+		}
+	}
+	return messages, trailer, nil
+}
+
+func protoToJSON(message []byte, schema *ProtoSchema) ([]byte, error) {
+	if schema == nil {
+		fields, err := decodeRawProto(message)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(fields)
+	}
+	m := dynamicpb.NewMessage(schema.message)
+	if err := proto.Unmarshal(message, m); err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(m)
+}
+
+// rawProtoFields is a message decoded without schema. Keys are field numbers
+// and wire types (e.g. "1:varint") in order of appearance.
+type rawProtoFields struct {
+	keys   []string
+	values map[string][]interface{}
+}
+
+func (f *rawProtoFields) add(key string, value interface{}) {
+	if _, ok := f.values[key]; !ok {
+		f.keys = append(f.keys, key)
+	}
+	f.values[key] = append(f.values[key], value)
+}
+
+// MarshalJSON marshals fields in order of appearance. Repeated fields are
+// marshaled as arrays.
+func (f *rawProtoFields) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, key := range f.keys {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		encodedKey, _ := json.Marshal(key)
+		buffer.Write(encodedKey)
+		buffer.WriteByte(':')
+
+		var value interface{} = f.values[key]
+		if len(f.values[key]) == 1 {
+			value = f.values[key][0]
+		}
+		encodedValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(encodedValue)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// decodeRawProto decodes message in the manner of `protoc --decode_raw`.
+// Length-delimited fields are shown as strings if they are printable text,
+// as embedded messages if they can be decoded, and as base64 otherwise.
+func decodeRawProto(message []byte) (*rawProtoFields, error) {
+	fields := &rawProtoFields{values: map[string][]interface{}{}}
+	for len(message) > 0 {
+		number, wireType, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		message = message[n:]
+		key := strconv.Itoa(int(number)) + ":"
+
+		switch wireType {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(message)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			message = message[n:]
+			fields.add(key+"varint", v)
+		case protowire.Fixed32Type:
+			v, n := protowire.ConsumeFixed32(message)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			message = message[n:]
+			fields.add(key+"fixed32", v)
+		case protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(message)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			message = message[n:]
+			fields.add(key+"fixed64", v)
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(message)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			message = message[n:]
+			if isPrintableText(v) {
+				fields.add(key+"string", string(v))
+			} else if embedded, err := decodeRawProto(v); err == nil {
+				fields.add(key+"message", embedded)
+			} else {
+				fields.add(key+"bytes", base64.StdEncoding.EncodeToString(v))
+			}
+		default:
+			// Groups are deprecated and not supported
+			return nil, errors.Errorf("unsupported wire type: %d", wireType)
+		}
+	}
+	return fields, nil
+}
+
+func isPrintableText(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,211 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// writeDescriptorSet writes a descriptor set of the following file:
+//
+//	syntax = "proto3";
+//	package test;
+//	message User {
+//	    int64 id = 1;
+//	    string name = 2;
+//	    repeated string tags = 3;
+//	}
+func writeDescriptorSet(t *testing.T) string {
+	field := func(name string, number int32, label descriptorpb.FieldDescriptorProto_Label, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    label.Enum(),
+			Type:     typ.Enum(),
+		}
+	}
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("test.proto"),
+				Package: proto.String("test"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("User"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("id", 1, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+							field("name", 2, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+							field("tags", 3, descriptorpb.FieldDescriptorProto_LABEL_REPEATED, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+						},
+					},
+				},
+			},
+		},
+	}
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatalf("failed to marshal descriptor set: err=%+v", err)
+	}
+	file, err := ioutil.TempFile("", "descriptor")
+	if err != nil {
+		t.Fatalf("failed to create temp file: err=%+v", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		t.Fatalf("failed to write descriptor set: err=%+v", err)
+	}
+	return file.Name()
+}
+
+// encodeUser encodes test.User{id: 42, name: "foo", tags: ["a", "b"]}.
+func encodeUser() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, 42)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, "foo")
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendString(b, "a")
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendString(b, "b")
+	return b
+}
+
+func grpcWebFrame(flag byte, data []byte) []byte {
+	frame := []byte{flag, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+	return append(frame, data...)
+}
+
+func TestPrettyPrinter_PrintBody_Protobuf(t *testing.T) {
+	descriptorSet := writeDescriptorSet(t)
+	defer os.Remove(descriptorSet)
+	schema, err := LoadProtoSchema(descriptorSet, "test.User")
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	var nested []byte
+	nested = protowire.AppendTag(nested, 1, protowire.BytesType)
+	nested = protowire.AppendBytes(nested, encodeUser())
+	nested = protowire.AppendTag(nested, 2, protowire.Fixed32Type)
+	nested = protowire.AppendFixed32(nested, 7)
+	nested = protowire.AppendTag(nested, 3, protowire.BytesType)
+	nested = protowire.AppendBytes(nested, []byte{0xff, 0x00})
+
+	testCases := []struct {
+		title       string
+		schema      *ProtoSchema
+		body        []byte
+		contentType string
+		expected    string
+	}{
+		{
+			title:       "With schema",
+			schema:      schema,
+			body:        encodeUser(),
+			contentType: "application/x-protobuf",
+			expected: strings.Join([]string{
+				`{`,
+				`    "id": "42",`,
+				`    "name": "foo",`,
+				`    "tags": [`,
+				`        "a",`,
+				`        "b"`,
+				`    ]`,
+				"}\n",
+			}, "\n"),
+		},
+		{
+			title:       "Without schema",
+			body:        nested,
+			contentType: "application/x-protobuf",
+			expected: strings.Join([]string{
+				`{`,
+				`    "1:message": {`,
+				`        "1:varint": 42,`,
+				`        "2:string": "foo",`,
+				`        "3:string": [`,
+				`            "a",`,
+				`            "b"`,
+				`        ]`,
+				`    },`,
+				`    "2:fixed32": 7,`,
+				`    "3:bytes": "/wA="`,
+				"}\n",
+			}, "\n"),
+		},
+		{
+			title:  "gRPC-Web",
+			schema: schema,
+			body: bytes.Join([][]byte{
+				grpcWebFrame(0x00, encodeUser()),
+				grpcWebFrame(0x00, []byte{}),
+				grpcWebFrame(0x80, []byte("grpc-status:0\r\ngrpc-message:OK\r\n")),
+			}, nil),
+			contentType: "application/grpc-web+proto",
+			expected: strings.Join([]string{
+				`{`,
+				`    "id": "42",`,
+				`    "name": "foo",`,
+				`    "tags": [`,
+				`        "a",`,
+				`        "b"`,
+				`    ]`,
+				`}`,
+				`{}`,
+				`grpc-message: OK`,
+				`grpc-status: 0`,
+				``,
+				``,
+			}, "\n"),
+		},
+		{
+			title:       "Malformed",
+			body:        []byte{0x0a, 0x05, 0x01},
+			contentType: "application/x-protobuf",
+			expected:    "\n\x05\x01",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var buffer strings.Builder
+			printer := NewPrettyPrinter(PrettyPrinterConfig{
+				Writer:      &buffer,
+				ProtoSchema: tt.schema,
+			})
+
+			// Exercise
+			if err := printer.PrintBody(bytes.NewReader(tt.body), tt.contentType); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s", tt.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestLoadProtoSchema_Error(t *testing.T) {
+	descriptorSet := writeDescriptorSet(t)
+	defer os.Remove(descriptorSet)
+
+	if _, err := LoadProtoSchema(descriptorSet, "test.Missing"); err == nil {
+		t.Errorf("error expected for missing message")
+	}
+	if _, err := LoadProtoSchema(descriptorSet+".missing", "test.User"); err == nil {
+		t.Errorf("error expected for missing file")
+	}
+}
@@ -0,0 +1,303 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/pkg/errors"
+)
+
+// Color is an entry of a palette.
+//
+// Basic is used on terminals that support only 8 colors, and also carries text
+// formats such as bold. RGB (0xRRGGBB) is used instead of the foreground color
+// of Basic on 256-color and truecolor terminals if HasRGB is true.
+type Color struct {
+	Basic  aurora.Color
+	RGB    uint32
+	HasRGB bool
+}
+
+// Theme is a set of palettes selected by --style.
+type Theme struct {
+	HeaderPalette HeaderPalette
+	JSONPalette   JSONPalette
+}
+
+const esc = "\033["
+
+// formatMask extracts text formats (bold, italic, etc.) from aurora.Color.
+const formatMask = aurora.BoldFm | aurora.FaintFm | aurora.ItalicFm | aurora.UnderlineFm |
+	aurora.SlowBlinkFm | aurora.RapidBlinkFm | aurora.ReverseFm | aurora.ConcealFm | aurora.CrossedOutFm
+
+func basic(c aurora.Color) Color {
+	return Color{Basic: c}
+}
+
+func rgb(basic aurora.Color, rgb uint32) Color {
+	return Color{Basic: basic, RGB: rgb, HasRGB: true}
+}
+
+var defaultTheme = Theme{
+	HeaderPalette: defaultHeaderPalette,
+	JSONPalette:   defaultJSONPalette,
+}
+
+var builtinThemes = map[string]*Theme{
+	"default": &defaultTheme,
+	"solarized": {
+		HeaderPalette: HeaderPalette{
+			Method:              rgb(aurora.BlueFg|aurora.BoldFm, 0x268bd2),
+			URL:                 rgb(aurora.CyanFg|aurora.BoldFm, 0x2aa198),
+			Proto:               rgb(aurora.MagentaFg, 0x6c71c4),
+			SuccessfulStatus:    rgb(aurora.GreenFg|aurora.BoldFm, 0x859900),
+			NonSuccessfulStatus: rgb(aurora.RedFg|aurora.BoldFm, 0xcb4b16),
+			FieldName:           rgb(aurora.BlueFg, 0x268bd2),
+			FieldValue:          rgb(aurora.CyanFg, 0x2aa198),
+			FieldSeparator:      rgb(aurora.WhiteFg, 0x93a1a1),
+		},
+		JSONPalette: JSONPalette{
+			Key:       rgb(aurora.BlueFg, 0x268bd2),
+			String:    rgb(aurora.CyanFg, 0x2aa198),
+			Number:    rgb(aurora.MagentaFg, 0xd33682),
+			Boolean:   rgb(aurora.YellowFg|aurora.BoldFm, 0xb58900),
+			Null:      rgb(aurora.RedFg|aurora.BoldFm, 0xdc322f),
+			Delimiter: rgb(aurora.WhiteFg, 0x93a1a1),
+		},
+	},
+	"monokai": {
+		HeaderPalette: HeaderPalette{
+			Method:              rgb(aurora.RedFg|aurora.BoldFm, 0xf92672),
+			URL:                 rgb(aurora.GreenFg|aurora.BoldFm, 0xa6e22e),
+			Proto:               rgb(aurora.CyanFg, 0x66d9ef),
+			SuccessfulStatus:    rgb(aurora.GreenFg|aurora.BoldFm, 0xa6e22e),
+			NonSuccessfulStatus: rgb(aurora.YellowFg|aurora.BoldFm, 0xfd971f),
+			FieldName:           rgb(aurora.CyanFg, 0x66d9ef),
+			FieldValue:          rgb(aurora.YellowFg, 0xe6db74),
+			FieldSeparator:      rgb(aurora.WhiteFg, 0xf8f8f2),
+		},
+		JSONPalette: JSONPalette{
+			Key:       rgb(aurora.RedFg, 0xf92672),
+			String:    rgb(aurora.YellowFg, 0xe6db74),
+			Number:    rgb(aurora.MagentaFg, 0xae81ff),
+			Boolean:   rgb(aurora.MagentaFg|aurora.BoldFm, 0xae81ff),
+			Null:      rgb(aurora.MagentaFg|aurora.BoldFm, 0xae81ff),
+			Delimiter: rgb(aurora.WhiteFg, 0xf8f8f2),
+		},
+	},
+	"light": {
+		HeaderPalette: HeaderPalette{
+			Method:              rgb(aurora.BlackFg|aurora.BoldFm, 0x24292e),
+			URL:                 rgb(aurora.BlueFg|aurora.BoldFm, 0x0550ae),
+			Proto:               rgb(aurora.MagentaFg, 0x8250df),
+			SuccessfulStatus:    rgb(aurora.GreenFg|aurora.BoldFm, 0x116329),
+			NonSuccessfulStatus: rgb(aurora.RedFg|aurora.BoldFm, 0xcf222e),
+			FieldName:           rgb(aurora.BlackFg, 0x24292e),
+			FieldValue:          rgb(aurora.BlueFg, 0x0a3069),
+			FieldSeparator:      rgb(aurora.BlackFg, 0x57606a),
+		},
+		JSONPalette: JSONPalette{
+			Key:       rgb(aurora.BlueFg, 0x0550ae),
+			String:    rgb(aurora.GreenFg, 0x116329),
+			Number:    rgb(aurora.MagentaFg, 0x8250df),
+			Boolean:   rgb(aurora.RedFg|aurora.BoldFm, 0xcf222e),
+			Null:      rgb(aurora.RedFg|aurora.BoldFm, 0xcf222e),
+			Delimiter: rgb(aurora.BlackFg, 0x57606a),
+		},
+	},
+}
+
+// ThemeNames returns the names of the built-in themes.
+func ThemeNames() []string {
+	var names []string
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTheme returns the built-in theme named style. If there is no such theme,
+// style is regarded as a path to a JSON file like:
+//
+//	{
+//	    "HeaderPalette": {"Method": "bold #268bd2", "URL": "cyan"},
+//	    "JSONPalette": {"Key": "blue", "String": "#2aa198"}
+//	}
+//
+// Colors omitted in the file are taken from the default theme.
+func LoadTheme(style string) (*Theme, error) {
+	if theme, ok := builtinThemes[style]; ok {
+		return theme, nil
+	}
+
+	data, err := ioutil.ReadFile(style)
+	if os.IsNotExist(err) {
+		return nil, errors.Errorf("unknown style (must be one of %s, or a path to a theme file): %s",
+			strings.Join(ThemeNames(), ", "), style)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading theme file '%s'", style)
+	}
+	theme := defaultTheme
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return nil, errors.Wrapf(err, "parsing theme file '%s'", style)
+	}
+	return &theme, nil
+}
+
+var basicColorNames = map[string]aurora.Color{
+	"black":   aurora.BlackFg,
+	"red":     aurora.RedFg,
+	"green":   aurora.GreenFg,
+	"yellow":  aurora.YellowFg,
+	"blue":    aurora.BlueFg,
+	"magenta": aurora.MagentaFg,
+	"cyan":    aurora.CyanFg,
+	"white":   aurora.WhiteFg,
+}
+
+var formatNames = map[string]aurora.Color{
+	"bold":      aurora.BoldFm,
+	"faint":     aurora.FaintFm,
+	"italic":    aurora.ItalicFm,
+	"underline": aurora.UnderlineFm,
+	"bright":    aurora.BrightFg,
+}
+
+// UnmarshalText parses a space-separated color specification such as
+// "bold green #859900". A word is one of formats (bold, faint, italic,
+// underline, bright), basic color names (black, red, green, yellow, blue,
+// magenta, cyan, white) or an RGB color (#rrggbb). If only an RGB color is
+// given, the nearest basic color is used on 8-color terminals. At most one
+// basic color and one RGB color may be given.
+func (c *Color) UnmarshalText(text []byte) error {
+	var color Color
+	var hasBasic bool
+	for _, word := range strings.Fields(strings.ToLower(string(text))) {
+		if fg, ok := basicColorNames[word]; ok {
+			if hasBasic {
+				return errors.Errorf("more than one basic color in color: %s", text)
+			}
+			color.Basic |= fg
+			hasBasic = true
+		} else if fm, ok := formatNames[word]; ok {
+			color.Basic |= fm
+		} else if strings.HasPrefix(word, "#") && len(word) == 7 {
+			v, err := strconv.ParseUint(word[1:], 16, 32)
+			if err != nil {
+				return errors.Errorf("invalid RGB color: %s", word)
+			}
+			if color.HasRGB {
+				return errors.Errorf("more than one RGB color in color: %s", text)
+			}
+			color.RGB = uint32(v)
+			color.HasRGB = true
+		} else {
+			return errors.Errorf("invalid word in color: %s", word)
+		}
+	}
+	if color.HasRGB && !hasBasic {
+		color.Basic |= nearestBasicColor(color.RGB)
+	}
+	*c = color
+	return nil
+}
+
+// colorize renders arg in color c according to the color depth.
+// It returns arg itself if colors are disabled.
+func colorize(a aurora.Aurora, enableColor bool, depth ColorDepth, arg interface{}, c Color) interface{} {
+	if !enableColor || !c.HasRGB || depth == ColorDepth8 {
+		return a.Colorize(arg, c.Basic)
+	}
+
+	var params []string
+	if formats := (c.Basic & formatMask).Nos(false); formats != "" {
+		params = append(params, formats)
+	}
+	r, g, b := splitRGB(c.RGB)
+	if depth == ColorDepth256 {
+		params = append(params, fmt.Sprintf("38;5;%d", nearest256Color(c.RGB)))
+	} else {
+		params = append(params, fmt.Sprintf("38;2;%d;%d;%d", r, g, b))
+	}
+	return fmt.Sprintf("%s%sm%v%s0m", esc, strings.Join(params, ";"), arg, esc)
+}
+
+func splitRGB(c uint32) (int, int, int) {
+	return int(c>>16) & 0xff, int(c>>8) & 0xff, int(c) & 0xff
+}
+
+func distance(r1, g1, b1, r2, g2, b2 int) int {
+	return (r1-r2)*(r1-r2) + (g1-g2)*(g1-g2) + (b1-b2)*(b1-b2)
+}
+
+// nearest256Color returns the index of the color nearest to c in the xterm
+// 256-color palette (only the 6x6x6 color cube and the grayscale ramp are used).
+func nearest256Color(c uint32) int {
+	r, g, b := splitRGB(c)
+	levels := []int{0, 95, 135, 175, 215, 255}
+	nearestLevel := func(v int) int {
+		best := 0
+		for i, level := range levels {
+			if abs(level-v) < abs(levels[best]-v) {
+				best = i
+			}
+		}
+		return best
+	}
+
+	ri, gi, bi := nearestLevel(r), nearestLevel(g), nearestLevel(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	cubeDistance := distance(r, g, b, levels[ri], levels[gi], levels[bi])
+
+	grayIndex := ((r+g+b)/3 - 8 + 5) / 10
+	if grayIndex < 0 {
+		grayIndex = 0
+	} else if grayIndex > 23 {
+		grayIndex = 23
+	}
+	gray := 8 + 10*grayIndex
+	if distance(r, g, b, gray, gray, gray) < cubeDistance {
+		return 232 + grayIndex
+	}
+	return cube
+}
+
+// nearestBasicColor returns the foreground color of the 8-color palette nearest to c.
+func nearestBasicColor(c uint32) aurora.Color {
+	r, g, b := splitRGB(c)
+	candidates := []struct {
+		color   aurora.Color
+		r, g, b int
+	}{
+		{aurora.BlackFg, 0, 0, 0},
+		{aurora.RedFg, 205, 0, 0},
+		{aurora.GreenFg, 0, 205, 0},
+		{aurora.YellowFg, 205, 205, 0},
+		{aurora.BlueFg, 0, 0, 238},
+		{aurora.MagentaFg, 205, 0, 205},
+		{aurora.CyanFg, 0, 205, 205},
+		{aurora.WhiteFg, 229, 229, 229},
+	}
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if distance(r, g, b, candidate.r, candidate.g, candidate.b) < distance(r, g, b, best.r, best.g, best.b) {
+			best = candidate
+		}
+	}
+	return best.color
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
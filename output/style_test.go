@@ -0,0 +1,150 @@
+package output
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/logrusorgru/aurora"
+)
+
+func TestColor_UnmarshalText(t *testing.T) {
+	testCases := []struct {
+		spec          string
+		expected      Color
+		shouldBeError bool
+	}{
+		{spec: "green", expected: Color{Basic: aurora.GreenFg}},
+		{spec: "bold bright red", expected: Color{Basic: aurora.BoldFm | aurora.BrightFg | aurora.RedFg}},
+		{spec: "Italic Blue #268BD2", expected: Color{Basic: aurora.ItalicFm | aurora.BlueFg, RGB: 0x268bd2, HasRGB: true}},
+		{spec: "#00cd00", expected: Color{Basic: aurora.GreenFg, RGB: 0x00cd00, HasRGB: true}},
+		{spec: "purple", shouldBeError: true},
+		{spec: "#12345", shouldBeError: true},
+		{spec: "#gggggg", shouldBeError: true},
+		{spec: "red blue", shouldBeError: true},
+		{spec: "#000000 #ffffff", shouldBeError: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.spec, func(t *testing.T) {
+			var color Color
+			err := color.UnmarshalText([]byte(tt.spec))
+			if tt.shouldBeError {
+				if err == nil {
+					t.Errorf("error expected but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+			if color != tt.expected {
+				t.Errorf("unexpected color: expected=%+v, actual=%+v", tt.expected, color)
+			}
+		})
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	for _, name := range ThemeNames() {
+		if _, err := LoadTheme(name); err != nil {
+			t.Errorf("failed to load built-in theme %s: err=%+v", name, err)
+		}
+	}
+
+	if _, err := LoadTheme("no-such-theme"); err == nil {
+		t.Errorf("error expected for unknown theme but got nil")
+	}
+}
+
+func TestLoadTheme_InvalidFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "httpie-go-test-")
+	if err != nil {
+		t.Fatalf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`{"HeaderPalette": {"Method": "red blue"}}`)
+	file.Close()
+
+	_, err = LoadTheme(file.Name())
+	if err == nil {
+		t.Fatalf("error expected but got nil")
+	}
+	if !strings.Contains(err.Error(), "parsing theme file") {
+		t.Errorf("parse error should be reported: err=%v", err)
+	}
+}
+
+func TestLoadTheme_File(t *testing.T) {
+	// Setup
+	file, err := ioutil.TempFile("", "httpie-go-test-")
+	if err != nil {
+		t.Fatalf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`{"HeaderPalette": {"Method": "bold #ff0000"}, "JSONPalette": {"Key": "magenta"}}`)
+	file.Close()
+
+	// Exercise
+	theme, err := LoadTheme(file.Name())
+	if err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	// Verify
+	expectedMethod := Color{Basic: aurora.BoldFm | aurora.RedFg, RGB: 0xff0000, HasRGB: true}
+	if theme.HeaderPalette.Method != expectedMethod {
+		t.Errorf("unexpected Method: expected=%+v, actual=%+v", expectedMethod, theme.HeaderPalette.Method)
+	}
+	if theme.JSONPalette.Key != basic(aurora.MagentaFg) {
+		t.Errorf("unexpected Key: %+v", theme.JSONPalette.Key)
+	}
+	if theme.JSONPalette.String != defaultJSONPalette.String {
+		t.Errorf("omitted color should be taken from the default theme: %+v", theme.JSONPalette.String)
+	}
+}
+
+func TestColorize(t *testing.T) {
+	color := rgb(aurora.BoldFm|aurora.RedFg, 0xff8700)
+
+	testCases := []struct {
+		title       string
+		enableColor bool
+		depth       ColorDepth
+		expected    string
+	}{
+		{title: "Disabled", enableColor: false, depth: ColorDepthTrue, expected: "hello"},
+		{title: "8 colors", enableColor: true, depth: ColorDepth8, expected: "\033[1;31mhello\033[0m"},
+		{title: "256 colors", enableColor: true, depth: ColorDepth256, expected: "\033[1;38;5;208mhello\033[0m"},
+		{title: "Truecolor", enableColor: true, depth: ColorDepthTrue, expected: "\033[1;38;2;255;135;0mhello\033[0m"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			a := aurora.NewAurora(tt.enableColor)
+			actual := fmt.Sprint(colorize(a, tt.enableColor, tt.depth, "hello", color))
+			if actual != tt.expected {
+				t.Errorf("unexpected output: expected=%q, actual=%q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNearest256Color(t *testing.T) {
+	testCases := []struct {
+		rgb      uint32
+		expected int
+	}{
+		{rgb: 0x000000, expected: 16},
+		{rgb: 0xffffff, expected: 231},
+		{rgb: 0xff8700, expected: 208},
+		{rgb: 0x808080, expected: 244},
+	}
+	for _, tt := range testCases {
+		if actual := nearest256Color(tt.rgb); actual != tt.expected {
+			t.Errorf("unexpected color index for %06x: expected=%d, actual=%d", tt.rgb, tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
+)
+
+// View is a way to render JSON bodies.
+type View int
+
+const (
+	JSONView View = iota
+	// TableView renders an array of objects as a table. Other JSON values are
+	// rendered in the same way as JSONView.
+	TableView
+)
+
+const (
+	tableColumnGap      = "  "
+	tableMinColumnWidth = 3
+	tableEllipsis       = "…"
+)
+
+type table struct {
+	columns []string
+	rows    [][]tableCell
+	// true if all cells in the column are numbers (they are aligned to the right)
+	numeric []bool
+}
+
+type tableCell struct {
+	text string
+	kind string // kind of the JSON value (see kindOfValue)
+}
+
+// newTable builds a table from tokens if tokens is an array of objects that
+// have the same set of keys. Otherwise, it returns nil. If columns is empty,
+// all keys are used as the columns in order of appearance in the first object.
+// It is an error if columns contains a name that is not a key of the objects.
+func newTable(tokens []json.Token, columns []string) (*table, error) {
+	end, ok := valueEnd(tokens, 0)
+	if !ok || end != len(tokens) || kindOfValue(tokens) != "array" {
+		return nil, nil
+	}
+	_, elements := children(tokens)
+	if len(elements) == 0 {
+		return nil, nil
+	}
+
+	var keys []string
+	var objects []map[string][]json.Token
+	for i, element := range elements {
+		if kindOfValue(element) != "object" {
+			return nil, nil
+		}
+		objectKeys, values := children(element)
+		object := map[string][]json.Token{}
+		for j, key := range objectKeys {
+			object[key] = values[j]
+		}
+		if i == 0 {
+			for _, key := range objectKeys {
+				if !containsString(keys, key) {
+					keys = append(keys, key)
+				}
+			}
+		} else if !sameKeys(object, objects[0]) {
+			return nil, nil
+		}
+		objects = append(objects, object)
+	}
+
+	if len(columns) == 0 {
+		columns = keys
+	}
+	for _, column := range columns {
+		if _, ok := objects[0][column]; !ok {
+			return nil, errors.Errorf("unknown column: %s (must be one of %s)", column, strings.Join(keys, ", "))
+		}
+	}
+
+	t := &table{columns: columns, numeric: make([]bool, len(columns))}
+	for i := range t.numeric {
+		t.numeric[i] = true
+	}
+	for _, object := range objects {
+		row := make([]tableCell, len(columns))
+		for i, column := range columns {
+			row[i] = newTableCell(object[column])
+			if row[i].kind != "number" {
+				t.numeric[i] = false
+			}
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t, nil
+}
+
+// sameKeys reports whether a and b have the same set of keys.
+func sameKeys(a, b map[string][]json.Token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+var tableCellEscaper = strings.NewReplacer("\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func newTableCell(value []json.Token) tableCell {
+	kind := kindOfValue(value)
+	switch kind {
+	case "string":
+		return tableCell{text: tableCellEscaper.Replace(value[0].(string)), kind: kind}
+	case "number":
+		return tableCell{text: value[0].(json.Number).String(), kind: kind}
+	case "boolean":
+		return tableCell{text: fmt.Sprint(value[0]), kind: kind}
+	case "null":
+		return tableCell{text: "null", kind: kind}
+	default:
+		// nested arrays and objects are rendered in compact JSON
+		var buffer bytes.Buffer
+		encodeTokens(&buffer, value)
+		return tableCell{text: buffer.String(), kind: kind}
+	}
+}
+
+// columnWidths returns the width of each column. If maxWidth > 0, wide columns
+// are narrowed so that the table fits in maxWidth as far as possible.
+func (t *table) columnWidths(maxWidth int) []int {
+	widths := make([]int, len(t.columns))
+	for i, column := range t.columns {
+		widths[i] = runewidth.StringWidth(column)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if w := runewidth.StringWidth(cell.text); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	if maxWidth <= 0 {
+		return widths
+	}
+
+	total := len(tableColumnGap) * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > maxWidth {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= tableMinColumnWidth {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+func (p *PrettyPrinter) printTable(t *table) error {
+	widths := t.columnWidths(p.terminalWidth)
+
+	// Header
+	cells := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		cells[i] = p.formatTableCell(column, p.jsonPalette.Key, widths[i], t.numeric[i])
+	}
+	p.printTableRow(cells)
+	for i, width := range widths {
+		cells[i] = fmt.Sprint(p.colorize(strings.Repeat("─", width), p.jsonPalette.Delimiter))
+	}
+	p.printTableRow(cells)
+
+	// Rows
+	for _, row := range t.rows {
+		for i, cell := range row {
+			cells[i] = p.formatTableCell(cell.text, p.tableCellColor(cell.kind), widths[i], t.numeric[i])
+		}
+		p.printTableRow(cells)
+	}
+	return nil
+}
+
+func (p *PrettyPrinter) printTableRow(cells []string) {
+	// Do not print trailing spaces
+	fmt.Fprintln(p.writer, strings.TrimRight(strings.Join(cells, tableColumnGap), " "))
+}
+
+func (p *PrettyPrinter) tableCellColor(kind string) Color {
+	switch kind {
+	case "string":
+		return p.jsonPalette.String
+	case "number":
+		return p.jsonPalette.Number
+	case "boolean":
+		return p.jsonPalette.Boolean
+	case "null":
+		return p.jsonPalette.Null
+	default:
+		return p.jsonPalette.Delimiter
+	}
+}
+
+// formatTableCell truncates and pads text to width, and colorizes it.
+func (p *PrettyPrinter) formatTableCell(text string, color Color, width int, alignRight bool) string {
+	text = runewidth.Truncate(text, width, tableEllipsis)
+	padding := strings.Repeat(" ", width-runewidth.StringWidth(text))
+	if text == "" {
+		return padding
+	}
+	if alignRight {
+		return fmt.Sprintf("%s%s", padding, p.colorize(text, color))
+	}
+	return fmt.Sprintf("%s%s", p.colorize(text, color), padding)
+}
@@ -0,0 +1,118 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrettyPrinter_PrintBody_TableView(t *testing.T) {
+	testCases := []struct {
+		title         string
+		body          string
+		columns       []string
+		terminalWidth int
+		expected      string
+	}{
+		{
+			title: "Array of objects",
+			body:  `[{"id": 1, "name": "foo", "tags": ["a"]}, {"tags": [], "id": 23, "name": "寿司"}, {"id": 4.5, "name": null, "tags": null}]`,
+			expected: strings.Join([]string{
+				" id  name  tags",
+				"───  ────  ─────",
+				"  1  foo   [\"a\"]",
+				" 23  寿司  []",
+				"4.5  null  null",
+				"",
+			}, "\n"),
+		},
+		{
+			title:   "Selected columns",
+			body:    `[{"id": 1, "name": "foo"}, {"id": 2, "name": "bar"}]`,
+			columns: []string{"name"},
+			expected: strings.Join([]string{
+				"name",
+				"────",
+				"foo",
+				"bar",
+				"",
+			}, "\n"),
+		},
+		{
+			title:    "Different keys",
+			body:     `[{"id": 1}, {"id": 2, "ok": true}]`,
+			expected: "[\n    {\n        \"id\": 1\n    },\n    {\n        \"id\": 2,\n        \"ok\": true\n    }\n]\n",
+		},
+		{
+			title:         "Narrow terminal",
+			body:          `[{"id": 1, "description": "a very long description", "x": "hello\nworld"}]`,
+			terminalWidth: 24,
+			expected: strings.Join([]string{
+				"id  descript…  x",
+				"──  ─────────  ─────────",
+				" 1  a very l…  hello\\nw…",
+				"",
+			}, "\n"),
+		},
+		{
+			title: "Not an array of objects",
+			body:  `[{"id": 1}, 2]`,
+			expected: strings.Join([]string{
+				`[`,
+				`    {`,
+				`        "id": 1`,
+				`    },`,
+				`    2`,
+				"]\n",
+			}, "\n"),
+		},
+		{
+			title:    "Empty array",
+			body:     `[]`,
+			expected: "[]\n",
+		},
+		{
+			title:    "Multiple values",
+			body:     `[{"id": 1}] [{"id": 2}]`,
+			expected: "[\n    {\n        \"id\": 1\n    }\n]\n[\n    {\n        \"id\": 2\n    }\n]\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			// Setup
+			var buffer strings.Builder
+			printer := NewPrettyPrinter(PrettyPrinterConfig{
+				Writer:        &buffer,
+				EnableColor:   false,
+				View:          TableView,
+				Columns:       tt.columns,
+				TerminalWidth: tt.terminalWidth,
+			})
+
+			// Exercise
+			err := printer.PrintBody(strings.NewReader(tt.body), "application/json")
+			if err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			// Verify
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s\n", tt.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestPrettyPrinter_PrintBody_TableView_UnknownColumn(t *testing.T) {
+	var buffer strings.Builder
+	printer := NewPrettyPrinter(PrettyPrinterConfig{
+		Writer:  &buffer,
+		View:    TableView,
+		Columns: []string{"name", "missing"},
+	})
+
+	err := printer.PrintBody(strings.NewReader(`[{"id": 1, "name": "foo"}]`), "application/json")
+	if err == nil {
+		t.Errorf("error expected for unknown column but got nil")
+	}
+}
@@ -0,0 +1,160 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
+)
+
+// Keys of phases in Timing.
+const (
+	TimingDNS      = "dns"
+	TimingConnect  = "connect"
+	TimingTLS      = "tls"
+	TimingTTFB     = "ttfb"
+	TimingTransfer = "transfer"
+)
+
+// timingPhaseNames are the names of phases printed in the waterfall, in the
+// order of the exchange.
+var timingPhaseNames = []struct {
+	key  string
+	name string
+}{
+	{TimingDNS, "DNS lookup"},
+	{TimingConnect, "TCP connect"},
+	{TimingTLS, "TLS handshake"},
+	{TimingTTFB, "Time to first byte"},
+	{TimingTransfer, "Content transfer"},
+}
+
+// timingBarWidth is the number of columns of the whole waterfall.
+const timingBarWidth = 40
+
+// TimingPhase is a phase of an exchange such as DNS lookup.
+type TimingPhase struct {
+	Key      string        // one of TimingDNS, TimingConnect, etc.
+	Start    time.Duration // since the start of the exchange
+	Duration time.Duration
+}
+
+// Timing is the breakdown of the time an exchange took. Phases that did not
+// happen (e.g. TCP connect on a reused connection) are omitted.
+type Timing struct {
+	Phases []TimingPhase
+	Total  time.Duration
+	Reused bool // whether the connection was reused
+}
+
+// phase returns the phase of key, or nil if it did not happen.
+func (t *Timing) phase(key string) *TimingPhase {
+	for i := range t.Phases {
+		if t.Phases[i].Key == key {
+			return &t.Phases[i]
+		}
+	}
+	return nil
+}
+
+// writeTimingWaterfall writes each phase with a bar positioned in proportion
+// to its start and duration.
+func writeTimingWaterfall(
+	w io.Writer,
+	timing *Timing,
+	palette *HeaderPalette,
+	barColors []Color,
+	colorize func(arg interface{}, color Color) interface{},
+) {
+	type row struct {
+		name     string
+		duration string
+		bar      string
+		color    Color
+	}
+	var rows []row
+	for i, phaseName := range timingPhaseNames {
+		phase := timing.phase(phaseName.key)
+		if phase == nil {
+			continue
+		}
+		offset, length := 0, 1
+		if timing.Total > 0 {
+			offset = int(int64(phase.Start) * timingBarWidth / int64(timing.Total))
+			length = int(int64(phase.Duration) * timingBarWidth / int64(timing.Total))
+		}
+		if offset >= timingBarWidth {
+			offset = timingBarWidth - 1
+		}
+		// Short phases are still visible
+		if length < 1 {
+			length = 1
+		}
+		if offset+length > timingBarWidth {
+			length = timingBarWidth - offset
+		}
+		rows = append(rows, row{
+			name:     phaseName.name,
+			duration: formatElapsed(phase.Duration),
+			bar:      strings.Repeat(" ", offset) + strings.Repeat("█", length),
+			color:    barColors[i%len(barColors)],
+		})
+	}
+	rows = append(rows, row{name: "Total", duration: formatElapsed(timing.Total)})
+
+	nameWidth, durationWidth := 0, 0
+	for _, r := range rows {
+		if n := runewidth.StringWidth(r.name); n > nameWidth {
+			nameWidth = n
+		}
+		if n := len(r.duration); n > durationWidth {
+			durationWidth = n
+		}
+	}
+	for _, r := range rows {
+		// Durations are aligned to the right
+		fmt.Fprintf(w, "%s%s %s%s",
+			colorize(r.name, palette.FieldName),
+			colorize(":", palette.FieldSeparator),
+			strings.Repeat(" ", nameWidth-runewidth.StringWidth(r.name)+durationWidth-len(r.duration)),
+			colorize(r.duration, palette.FieldValue))
+		if r.bar != "" {
+			fmt.Fprintf(w, "  %s", colorize(r.bar, r.color))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%s%s %s\n",
+		colorize("Connection reused", palette.FieldName),
+		colorize(":", palette.FieldSeparator),
+		colorize(yesNo(timing.Reused), palette.FieldValue))
+}
+
+// writeTimingJSON writes timing as a JSON object in a line. Durations are in
+// seconds, and phases that did not happen are 0.
+func writeTimingJSON(w io.Writer, timing *Timing) error {
+	var b strings.Builder
+	b.WriteString("{")
+	for _, phaseName := range timingPhaseNames {
+		var seconds float64
+		if phase := timing.phase(phaseName.key); phase != nil {
+			seconds = phase.Duration.Seconds()
+		}
+		fmt.Fprintf(&b, "%q:%s,", phaseName.key, formatSeconds(seconds))
+	}
+	fmt.Fprintf(&b, "\"total\":%s,\"reused\":%t}", formatSeconds(timing.Total.Seconds()), timing.Reused)
+	if _, err := fmt.Fprintln(w, b.String()); err != nil {
+		return errors.Wrap(err, "printing timing")
+	}
+	return nil
+}
+
+// formatSeconds formats seconds as a JSON number with microsecond precision.
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(math.Round(seconds*1e6)/1e6, 'f', -1, 64)
+}
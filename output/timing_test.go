@@ -0,0 +1,81 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTiming = Timing{
+	Phases: []TimingPhase{
+		{Key: TimingDNS, Start: 0, Duration: 10 * time.Millisecond},
+		{Key: TimingConnect, Start: 10 * time.Millisecond, Duration: 10 * time.Millisecond},
+		{Key: TimingTLS, Start: 20 * time.Millisecond, Duration: 20 * time.Millisecond},
+		{Key: TimingTTFB, Start: 40 * time.Millisecond, Duration: 40 * time.Millisecond},
+		{Key: TimingTransfer, Start: 80 * time.Millisecond, Duration: 1500 * time.Microsecond},
+	},
+	Total: 100 * time.Millisecond,
+}
+
+func TestPlainPrinter_PrintTiming(t *testing.T) {
+	testCases := []struct {
+		title    string
+		timing   Timing
+		expected string
+	}{
+		{
+			title:    "New connection",
+			timing:   testTiming,
+			expected: `{"dns":0.01,"connect":0.01,"tls":0.02,"ttfb":0.04,"transfer":0.0015,"total":0.1,"reused":false}` + "\n",
+		},
+		{
+			title: "Reused connection",
+			timing: Timing{
+				Phases: []TimingPhase{
+					{Key: TimingTTFB, Start: 0, Duration: 1234567 * time.Nanosecond},
+					{Key: TimingTransfer, Start: 1234567 * time.Nanosecond, Duration: 0},
+				},
+				Total:  1234567 * time.Nanosecond,
+				Reused: true,
+			},
+			expected: `{"dns":0,"connect":0,"tls":0,"ttfb":0.001235,"transfer":0,"total":0.001235,"reused":true}` + "\n",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			var buffer strings.Builder
+			printer := NewPlainPrinter(&buffer)
+
+			if err := PrintTiming(printer, &tt.timing); err != nil {
+				t.Fatalf("unexpected error: err=%+v", err)
+			}
+
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s", tt.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestPrettyPrinter_PrintTiming(t *testing.T) {
+	var buffer strings.Builder
+	printer := NewPrettyPrinter(PrettyPrinterConfig{Writer: &buffer})
+
+	if err := PrintTiming(printer, &testTiming); err != nil {
+		t.Fatalf("unexpected error: err=%+v", err)
+	}
+
+	expected := strings.Join([]string{
+		"DNS lookup:          10ms  " + strings.Repeat("█", 4),
+		"TCP connect:         10ms      " + strings.Repeat("█", 4),
+		"TLS handshake:       20ms          " + strings.Repeat("█", 8),
+		"Time to first byte:  40ms                  " + strings.Repeat("█", 16),
+		"Content transfer:   1.5ms                                  █",
+		"Total:              100ms",
+		"Connection reused: no",
+		"",
+	}, "\n")
+	if buffer.String() != expected {
+		t.Errorf("unexpected output: expected=\n%s\nactual=\n%s", expected, buffer.String())
+	}
+}
@@ -0,0 +1,179 @@
+package output
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// DefaultCertWarningDays is the default number of days before expiry from
+// which certificates are warned about.
+const DefaultCertWarningDays = 30
+
+var cipherSuiteNames = map[uint16]string{
+	tls.TLS_RSA_WITH_RC4_128_SHA:                "TLS_RSA_WITH_RC4_128_SHA",
+	tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA:           "TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+	tls.TLS_RSA_WITH_AES_128_CBC_SHA:            "TLS_RSA_WITH_AES_128_CBC_SHA",
+	tls.TLS_RSA_WITH_AES_256_CBC_SHA:            "TLS_RSA_WITH_AES_256_CBC_SHA",
+	tls.TLS_RSA_WITH_AES_128_CBC_SHA256:         "TLS_RSA_WITH_AES_128_CBC_SHA256",
+	tls.TLS_RSA_WITH_AES_128_GCM_SHA256:         "TLS_RSA_WITH_AES_128_GCM_SHA256",
+	tls.TLS_RSA_WITH_AES_256_GCM_SHA384:         "TLS_RSA_WITH_AES_256_GCM_SHA384",
+	tls.TLS_ECDHE_ECDSA_WITH_RC4_128_SHA:        "TLS_ECDHE_ECDSA_WITH_RC4_128_SHA",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA:    "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA:    "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA",
+	tls.TLS_ECDHE_RSA_WITH_RC4_128_SHA:          "TLS_ECDHE_RSA_WITH_RC4_128_SHA",
+	tls.TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA:     "TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA",
+	tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA:      "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+	tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA:      "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256: "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256",
+	tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256:   "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256",
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256:   "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256: "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384:   "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384: "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305:    "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305:  "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+	tls.TLS_AES_128_GCM_SHA256:                  "TLS_AES_128_GCM_SHA256",
+	tls.TLS_AES_256_GCM_SHA384:                  "TLS_AES_256_GCM_SHA384",
+	tls.TLS_CHACHA20_POLY1305_SHA256:            "TLS_CHACHA20_POLY1305_SHA256",
+}
+
+func cipherSuiteName(id uint16) string {
+	if name, ok := cipherSuiteNames[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%04X", id)
+}
+
+// tlsPrinter prints the TLS session and the certificate chain of a response.
+type tlsPrinter struct {
+	writer      io.Writer
+	palette     *HeaderPalette
+	colorize    func(arg interface{}, color Color) interface{}
+	warningDays int // certificates expiring within this number of days are warned about
+	now         time.Time
+}
+
+func (t *tlsPrinter) printTLS(state *tls.ConnectionState) {
+	if state == nil {
+		fmt.Fprintln(t.writer, t.colorize("Not a TLS connection", t.palette.FieldValue))
+		return
+	}
+
+	alpn := state.NegotiatedProtocol
+	if alpn == "" {
+		alpn = "(none)"
+	}
+	writeFields(t.writer, "", [][2]string{
+		{"TLS version", tlsVersionName(state.Version)},
+		{"Cipher suite", cipherSuiteName(state.CipherSuite)},
+		{"ALPN protocol", alpn},
+		{"OCSP stapling", yesNo(len(state.OCSPResponse) > 0)},
+		{"Session resumed", yesNo(state.DidResume)},
+	}, t.palette, t.colorize)
+
+	var warnings []string
+	for i, cert := range state.PeerCertificates {
+		fmt.Fprintln(t.writer)
+		fmt.Fprintf(t.writer, "%s\n", t.colorize(fmt.Sprintf("Certificate #%d:", i), t.palette.FieldName))
+		writeFields(t.writer, "  ", certificateFields(cert, t.now), t.palette, t.colorize)
+
+		days := daysUntil(cert.NotAfter, t.now)
+		if t.warningDays > 0 && days < t.warningDays {
+			warnings = append(warnings, expiryWarning(i, cert, days))
+		}
+	}
+	if len(warnings) > 0 {
+		fmt.Fprintln(t.writer)
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(t.writer, "%s\n", t.colorize("WARNING: "+warning, t.palette.NonSuccessfulStatus))
+	}
+}
+
+// certificateFields returns the name and the value of each line of cert.
+func certificateFields(cert *x509.Certificate, now time.Time) [][2]string {
+	fields := [][2]string{
+		{"Subject", cert.Subject.String()},
+		{"Issuer", cert.Issuer.String()},
+	}
+	if sans := subjectAltNames(cert); len(sans) > 0 {
+		fields = append(fields, [2]string{"SANs", strings.Join(sans, ", ")})
+	}
+
+	notAfter := formatCertTime(cert.NotAfter)
+	if days := daysUntil(cert.NotAfter, now); days >= 0 {
+		notAfter += fmt.Sprintf(" (%s left)", pluralize(days, "day"))
+	} else {
+		notAfter += fmt.Sprintf(" (expired %s ago)", pluralize(-days, "day"))
+	}
+	return append(fields,
+		[2]string{"Not before", formatCertTime(cert.NotBefore)},
+		[2]string{"Not after", notAfter},
+		[2]string{"SHA-256", certificateFingerprint(cert)},
+	)
+}
+
+// subjectAltNames returns DNS names, IP addresses, email addresses and URIs of cert.
+func subjectAltNames(cert *x509.Certificate) []string {
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+// certificateFingerprint returns the SHA-256 hash of cert like "AB:CD:...".
+func certificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hex, ":")
+}
+
+func formatCertTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
+
+// daysUntil returns the number of whole days from now to t, which is negative if t has passed.
+func daysUntil(t time.Time, now time.Time) int {
+	d := t.Sub(now)
+	if d < 0 {
+		// Round toward negative infinity so that just expired certificates are -1
+		return -int((-d + 24*time.Hour - 1) / (24 * time.Hour))
+	}
+	return int(d / (24 * time.Hour))
+}
+
+func expiryWarning(index int, cert *x509.Certificate, days int) string {
+	name := cert.Subject.CommonName
+	if name == "" {
+		name = cert.Subject.String()
+	}
+	switch {
+	case days < 0:
+		return fmt.Sprintf("certificate #%d (%s) expired %s ago", index, name, pluralize(-days, "day"))
+	case days == 0:
+		return fmt.Sprintf("certificate #%d (%s) expires within a day", index, name)
+	default:
+		return fmt.Sprintf("certificate #%d (%s) expires in %s", index, name, pluralize(days, "day"))
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,162 @@
+package output
+
+import (
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCertificate(t *testing.T, template *x509.Certificate) *x509.Certificate {
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	der, err := x509.CreateCertificate(nil, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: err=%+v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: err=%+v", err)
+	}
+	return cert
+}
+
+func TestPlainPrinter_PrintTLS(t *testing.T) {
+	now := time.Date(2020, 3, 10, 12, 0, 0, 0, time.UTC)
+	leaf := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com", Organization: []string{"Example"}},
+		DNSNames:     []string{"example.com", "www.example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("93.184.216.34")},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2020, 3, 20, 0, 0, 0, 0, time.UTC),
+	})
+	root := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Example Root CA"},
+		NotBefore:    time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	testCases := []struct {
+		title       string
+		state       *tls.ConnectionState
+		warningDays int
+		expected    string
+	}{
+		{
+			title: "Chain",
+			state: &tls.ConnectionState{
+				Version:            tls.VersionTLS13,
+				CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+				NegotiatedProtocol: "h2",
+				OCSPResponse:       []byte{0x30},
+				PeerCertificates:   []*x509.Certificate{leaf, root},
+			},
+			warningDays: 30,
+			expected: strings.Join([]string{
+				"TLS version:     TLS 1.3",
+				"Cipher suite:    TLS_AES_128_GCM_SHA256",
+				"ALPN protocol:   h2",
+				"OCSP stapling:   yes",
+				"Session resumed: no",
+				"",
+				"Certificate #0:",
+				"  Subject:    CN=example.com,O=Example",
+				"  Issuer:     CN=example.com,O=Example",
+				"  SANs:       example.com, www.example.com, 93.184.216.34",
+				"  Not before: 2020-01-01 00:00:00 UTC",
+				"  Not after:  2020-03-20 00:00:00 UTC (9 days left)",
+				"  SHA-256:    " + certificateFingerprint(leaf),
+				"",
+				"Certificate #1:",
+				"  Subject:    CN=Example Root CA",
+				"  Issuer:     CN=Example Root CA",
+				"  Not before: 2010-01-01 00:00:00 UTC",
+				"  Not after:  2030-01-01 00:00:00 UTC (3583 days left)",
+				"  SHA-256:    " + certificateFingerprint(root),
+				"",
+				"WARNING: certificate #0 (example.com) expires in 9 days",
+				"",
+			}, "\n"),
+		},
+		{
+			title: "Warning disabled",
+			state: &tls.ConnectionState{
+				Version:          tls.VersionTLS12,
+				CipherSuite:      tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+				DidResume:        true,
+				PeerCertificates: []*x509.Certificate{leaf},
+			},
+			warningDays: 0,
+			expected: strings.Join([]string{
+				"TLS version:     TLS 1.2",
+				"Cipher suite:    TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+				"ALPN protocol:   (none)",
+				"OCSP stapling:   no",
+				"Session resumed: yes",
+				"",
+				"Certificate #0:",
+				"  Subject:    CN=example.com,O=Example",
+				"  Issuer:     CN=example.com,O=Example",
+				"  SANs:       example.com, www.example.com, 93.184.216.34",
+				"  Not before: 2020-01-01 00:00:00 UTC",
+				"  Not after:  2020-03-20 00:00:00 UTC (9 days left)",
+				"  SHA-256:    " + certificateFingerprint(leaf),
+				"",
+			}, "\n"),
+		},
+		{
+			title:    "Not TLS",
+			state:    nil,
+			expected: "Not a TLS connection\n",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			var buffer strings.Builder
+			printer := &tlsPrinter{
+				writer:      &buffer,
+				palette:     &defaultHeaderPalette,
+				colorize:    func(arg interface{}, color Color) interface{} { return arg },
+				warningDays: tt.warningDays,
+				now:         now,
+			}
+			printer.printTLS(tt.state)
+
+			if buffer.String() != tt.expected {
+				t.Errorf("unexpected output: expected=\n%s\nactual=\n%s", tt.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestDaysUntil(t *testing.T) {
+	now := time.Date(2020, 3, 10, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		t        time.Time
+		expected int
+	}{
+		{t: now.Add(36 * time.Hour), expected: 1},
+		{t: now.Add(time.Hour), expected: 0},
+		{t: now.Add(-time.Hour), expected: -1},
+		{t: now.Add(-48 * time.Hour), expected: -2},
+	}
+	for _, tt := range testCases {
+		if actual := daysUntil(tt.t, now); actual != tt.expected {
+			t.Errorf("unexpected result of daysUntil(%v): expected=%d, actual=%d", tt.t, tt.expected, actual)
+		}
+	}
+}
+
+func TestCertificateFingerprint(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("hello")}
+	expected := "2C:F2:4D:BA:5F:B0:A3:0E:26:E8:3B:2A:C5:B9:E2:9E:1B:16:1E:5C:1F:A7:42:5E:73:04:33:62:93:8B:98:24"
+	if actual := certificateFingerprint(cert); actual != expected {
+		t.Errorf("unexpected fingerprint: expected=%s, actual=%s", expected, actual)
+	}
+}
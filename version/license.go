@@ -32,6 +32,36 @@ var Licenses = []License{
 		LicenseName: "MIT License",
 		Link:        "https://github.com/mattn/go-isatty/blob/master/LICENSE",
 	},
+	{
+		ModuleName:  "go-runewidth",
+		LicenseName: "MIT License",
+		Link:        "https://github.com/mattn/go-runewidth/blob/master/LICENSE",
+	},
+	{
+		ModuleName:  "cbor",
+		LicenseName: "MIT License",
+		Link:        "https://github.com/fxamacker/cbor/blob/master/LICENSE",
+	},
+	{
+		ModuleName:  "float16",
+		LicenseName: "MIT License",
+		Link:        "https://github.com/x448/float16/blob/master/LICENSE",
+	},
+	{
+		ModuleName:  "msgpack",
+		LicenseName: "BSD License",
+		Link:        "https://github.com/vmihailenco/msgpack/blob/v5/LICENSE",
+	},
+	{
+		ModuleName:  "tagparser",
+		LicenseName: "BSD License",
+		Link:        "https://github.com/vmihailenco/tagparser/blob/v2/LICENSE",
+	},
+	{
+		ModuleName:  "protobuf",
+		LicenseName: "BSD License",
+		Link:        "https://github.com/protocolbuffers/protobuf-go/blob/master/LICENSE",
+	},
 	{
 		ModuleName:  "getopt",
 		LicenseName: "BSD License",